@@ -18,6 +18,9 @@ import (
 
 	"github.com/ripta/axe/pkg/app"
 	"github.com/ripta/axe/pkg/kubelogs"
+	"github.com/ripta/axe/pkg/query"
+	"github.com/ripta/axe/pkg/ui"
+	"github.com/ripta/axe/pkg/ui/themes"
 )
 
 func main() {
@@ -41,6 +44,10 @@ func start(ctx context.Context) error {
 	}
 
 	root.PersistentFlags().Bool("debug", false, "Enable debug logs")
+	root.Flags().Bool("headless", false, "Write logs to stdout instead of starting the UI")
+	root.Flags().Duration("reorder", 0, "Hold lines for this long to show them in the order they were logged across containers, e.g., 2s; 0 shows lines as they arrive")
+	root.Flags().String("theme", "", fmt.Sprintf("Colour theme: one of %s, or the name or path of a YAML theme file (TOML is not supported)", strings.Join(themes.Names(), ", ")))
+	root.Flags().String("where", "", "Only show lines matching a query, e.g., 'priority>=WARNING && pod=~\"api-.*\"'")
 
 	kcf := genericclioptions.NewConfigFlags(true)
 	kcf.AddFlags(root.PersistentFlags())
@@ -60,6 +67,7 @@ func start(ctx context.Context) error {
 
 	f := cmdutil.NewFactory(kcf)
 	root.RunE = run(logger, f)
+	root.AddCommand(newTransformersCommand())
 
 	return root.ExecuteContext(ctx)
 }
@@ -74,13 +82,41 @@ func run(logger *log.Logger, f cmdutil.Factory) func(*cobra.Command, []string) e
 			return err
 		}
 
+		headless, err := cmd.Flags().GetBool("headless")
+		if err != nil {
+			return err
+		}
+
+		reorder, err := cmd.Flags().GetDuration("reorder")
+		if err != nil {
+			return err
+		}
+
+		theme, err := cmd.Flags().GetString("theme")
+		if err != nil {
+			return err
+		}
+
+		var q *query.Query
+		if where, err := cmd.Flags().GetString("where"); err != nil {
+			return err
+		} else if where != "" {
+			if q, err = query.Parse(where); err != nil {
+				return err
+			}
+		}
+
 		cs, err := f.KubernetesClientSet()
 		if err != nil {
 			return err
 		}
 
 		m := kubelogs.NewManager(logger, cs, 1*time.Second, 3*time.Minute, debug)
-		a := app.New(logger, m, debug)
+		m.SetReorderDelay(reorder)
+		a, err := app.New(logger, m, theme, debug)
+		if err != nil {
+			return err
+		}
 
 		nss, _, err := f.ToRawKubeConfigLoader().Namespace()
 		if err != nil {
@@ -90,7 +126,14 @@ func run(logger *log.Logger, f cmdutil.Factory) func(*cobra.Command, []string) e
 		for _, ns := range strings.Split(nss, ",") {
 			m.Watch(strings.TrimSpace(ns))
 		}
+		a.UI.SetNamespace(nss)
 
+		if headless {
+			return a.RunHeadless(ctx, os.Stdout, q)
+		}
+		if q != nil {
+			a.UI.PushFilter(ui.NewQueryFilter(a.Buffer, q))
+		}
 		return a.Run(ctx)
 	}
 }
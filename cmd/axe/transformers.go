@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ripta/axe/pkg/app"
+	"github.com/ripta/axe/pkg/config"
+	"github.com/ripta/axe/pkg/structstream"
+)
+
+func newTransformersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transformers",
+		Short: "Work with the transformers that parse log lines",
+	}
+
+	test := &cobra.Command{
+		Use:   "test [file...]",
+		Short: "Parse sample lines from files, or stdin, and show their fields",
+		Long: `Parse sample lines with the transformers defined in the configuration file,
+or in another file with the same layout, and show the fields of each line.`,
+		RunE: runTransformersTest,
+	}
+	test.Flags().Bool("all", false, "Also try the built-in transformers, as axe does")
+	test.Flags().StringP("file", "f", "", "Read the transformers from this file instead of the configuration file")
+	test.Flags().String("name", "", "Only try the transformer with this name")
+
+	cmd.AddCommand(test)
+	return cmd
+}
+
+func runTransformersTest(cmd *cobra.Command, args []string) error {
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return err
+	}
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return err
+	}
+
+	var settings config.Settings
+	if file != "" {
+		settings, err = config.ReadSettings(file)
+	} else {
+		settings, err = config.LoadSettings()
+	}
+	if err != nil {
+		return err
+	}
+
+	defs := settings.Transformers
+	if name != "" {
+		defs = nil
+		for _, d := range settings.Transformers {
+			if d.Name == name {
+				defs = append(defs, d)
+			}
+		}
+		if len(defs) == 0 {
+			return fmt.Errorf("no transformer named %q", name)
+		}
+	}
+
+	var transform structstream.Transformer
+	if all {
+		transform, err = app.Transformers(defs)
+	} else {
+		var ts []structstream.Transformer
+		ts, err = app.UserTransformers(defs)
+		transform = structstream.CombineTransformers(false, ts...)
+	}
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(args) == 0 {
+		return testLines(out, cmd.InOrStdin(), transform)
+	}
+	for _, arg := range args {
+		f, err := os.Open(arg)
+		if err != nil {
+			return err
+		}
+		err = testLines(out, f, transform)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testLines writes the fields of each line read from r, or that it did not
+// match any transformer.
+func testLines(w io.Writer, r io.Reader, transform structstream.Transformer) error {
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		s, ok := transform("", scanner.Text())
+		if !ok {
+			fmt.Fprintf(w, "%d: no match\n", n)
+			continue
+		}
+
+		status := ""
+		if !s.Complete {
+			status = " (incomplete)"
+		}
+		fmt.Fprintf(w, "%d: %s%s\n", n, s.Type, status)
+
+		tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', 0)
+		if !s.Timestamp.IsZero() {
+			fmt.Fprintf(tw, "  timestamp:\t%s\n", s.Timestamp.Format(time.RFC3339Nano))
+		}
+		fmt.Fprintf(tw, "  severity:\t%s\n", s.Severity)
+		fmt.Fprintf(tw, "  message:\t%s\n", s.Message)
+
+		keys := make([]string, 0, len(s.KV))
+		for k := range s.KV {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(tw, "  kv.%s:\t%v\n", k, s.KV[k])
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
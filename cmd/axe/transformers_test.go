@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ripta/axe/pkg/structstream"
+)
+
+func TestTestLines(t *testing.T) {
+	transform, err := structstream.NewRegexpTransformer(structstream.RegexpSpec{
+		Name:    "billing",
+		Pattern: `^(?P<timestamp>\S+) (?P<priority>[A-Z]+) \[(?P<txn>\w+)\] (?P<message>.*)$`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := strings.Join([]string{
+		`2020-10-17T14:32:05Z WARN [tx1] charged card`,
+		`not a billing line`,
+		`yesterday ERROR [tx2] declined`,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := testLines(&out, strings.NewReader(in), transform); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		`1: billing`,
+		`  timestamp: 2020-10-17T14:32:05Z`,
+		`  severity:  WARNING`,
+		`  message:   charged card`,
+		`  kv.txn:    tx1`,
+		`2: no match`,
+		`3: billing (incomplete)`,
+		`  severity: ERROR`,
+		`  message:  declined`,
+		`  kv.txn:   tx2`,
+		``,
+	}, "\n")
+	if got := out.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
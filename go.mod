@@ -19,6 +19,7 @@ require (
 	k8s.io/kube-openapi v0.0.0-20201106092651-fd18780d1fff // indirect
 	k8s.io/kubectl v0.19.3
 	k8s.io/utils v0.0.0-20201104234853-8146046b121e // indirect
+	sigs.k8s.io/yaml v1.1.0
 )
 
 replace k8s.io/api => k8s.io/api v0.16.7
@@ -3,43 +3,102 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2/views"
+	"github.com/ripta/axe/pkg/config"
 	"github.com/ripta/axe/pkg/iorate"
 	"github.com/ripta/axe/pkg/kubelogs"
 	"github.com/ripta/axe/pkg/logger"
+	"github.com/ripta/axe/pkg/query"
+	"github.com/ripta/axe/pkg/structstream"
 	"github.com/ripta/axe/pkg/ui"
 	"github.com/ripta/axe/pkg/ui/themes"
+	"github.com/ripta/axe/pkg/ui/widgets"
 )
 
+// bufferSize is the initial capacity of the structured line buffer.
+const bufferSize = 10000
+
+// detectSamples is the number of lines of each container image that are
+// sampled to detect its log format.
+const detectSamples = 50
+
+// lateMarker follows the prefix of lines that are shown out of order, because
+// they arrived too late to be reordered.
+const lateMarker = "(late) "
+
 // App is a controller that connects the LogManager (model) and the UI (view).
 type App struct {
 	App        *views.Application
+	Buffer     *structstream.Buffer
 	UI         *ui.UI
 	LogManager *kubelogs.Manager
 
-	debug bool
-	l     logger.Interface
+	debug     bool
+	l         logger.Interface
+	detector  *structstream.Detector
+	transform structstream.Transformer
 }
 
-func New(l logger.Interface, m *kubelogs.Manager, debug bool) *App {
-	style := themes.SolarizedDark()
+// New creates the app. The theme is the name or path of a theme, as read by
+// loadTheme, which overrides the theme in the user's settings if set.
+func New(l logger.Interface, m *kubelogs.Manager, theme string, debug bool) (*App, error) {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	formats, err := Formats(settings.Transformers)
+	if err != nil {
+		return nil, err
+	}
+	detector := structstream.NewDetector(formats, detectSamples, imageOf(m))
+	buf, err := structstream.New(bufferSize, detector.Transform)
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := ui.NewBindings(settings.Keys.Preset, settings.Keys.Bindings)
+	if err != nil {
+		return nil, err
+	}
+
+	if theme == "" {
+		theme = settings.Theme
+		// NO_COLOR asks for no colour in the terminal it is set in, which
+		// is more specific than the settings, but not than the flag.
+		if os.Getenv("NO_COLOR") != "" {
+			theme = themes.MonochromeName
+		}
+	}
+	style, err := loadTheme(theme)
+	if err != nil {
+		return nil, err
+	}
+
 	app := &views.Application{}
 
-	u := ui.New(app, style)
+	u := ui.New(app, style, buf)
+	u.SetBindings(bindings)
+	u.SetPodInfo(m.PodInfo)
+	u.SetDetector(detector)
 	app.SetRootWidget(u)
 
 	return &App{
 		App:        app,
+		Buffer:     buf,
 		UI:         u,
 		LogManager: m,
 
-		debug: debug,
-		l:     l,
-	}
+		debug:     debug,
+		l:         l,
+		detector:  detector,
+		transform: detector.Transform,
+	}, nil
 }
 
 func (a *App) Run(ctx context.Context) error {
@@ -76,7 +135,45 @@ func (a *App) Run(ctx context.Context) error {
 	go func() {
 		rate := iorate.New()
 		lrate := iorate.New()
+		srates := make(map[string]*iorate.Rate)
 		su := time.Tick(5 * time.Second)
+		asm := newAssembler(joinTimeout)
+		flush := time.Tick(joinTimeout / 2)
+
+		appendEvent := func(ev event) {
+			line := ev.line
+			// Lines are parsed as they are received, so lines
+			// without a timestamp are given the time received.
+			// Timestamps from the API server take precedence.
+			// Each line is sampled once, however often it is
+			// parsed.
+			a.detector.Sample(line.Source(), ev.Text())
+			idx := a.Buffer.AppendAt(line.Source(), ev.Text(), line.Timestamp)
+			s := a.Buffer.GetAt(idx)
+			pl := widgets.Line{
+				Index:    idx,
+				Source:   line.Source(),
+				Prefix:   prefix(line),
+				Text:     line.Text,
+				Severity: s.Severity,
+				Time:     s.Timestamp,
+				More:     ev.more,
+			}
+
+			if _, ok := srates[pl.Source]; !ok {
+				srates[pl.Source] = iorate.New()
+			}
+
+			rate.Add(len(pl.String()))
+			lrate.Add(1)
+			srates[pl.Source].Add(1)
+			a.App.PostFunc(func() {
+				a.UI.PagerAppend(pl)
+				if spool != nil {
+					spool.WriteString(pl.Prefix + pl.FullText() + "\n")
+				}
+			})
+		}
 
 		for {
 			select {
@@ -87,24 +184,47 @@ func (a *App) Run(ctx context.Context) error {
 						a.UI.SetMessage(fmt.Sprintf("axe: %s", line.Text))
 					})
 				case logger.LogLineTypeContainer:
-					msg := line.Name + "] " + line.Text
-
-					rate.Add(len(msg))
-					lrate.Add(1)
-					a.App.PostFunc(func() {
-						a.UI.PagerAppend(msg)
-						if spool != nil {
-							spool.WriteString(msg + "\n")
-						}
-					})
+					// Continuation lines, such as the frames of stack
+					// traces, are joined to the line they continue.
+					for _, ev := range asm.Add(line, time.Now()) {
+						appendEvent(ev)
+					}
+				}
+			case <-flush:
+				for _, ev := range asm.Flush(time.Now()) {
+					appendEvent(ev)
 				}
 			case <-su:
 				activeCnt, allCnt := a.LogManager.ContainerCount()
 				r := iorate.HumanizeBytes(rate.Calculate(time.Second))
 				l := int(lrate.Calculate(time.Second))
+
+				states := a.LogManager.Containers()
+				var waiting, backoff int
+				for _, state := range states {
+					switch state {
+					case kubelogs.ContainerStateWaiting:
+						waiting++
+					case kubelogs.ContainerStateBackoff:
+						backoff++
+					}
+				}
+				rates := make(map[string]float64, len(srates))
+				for src, sr := range srates {
+					rates[src] = sr.Calculate(time.Second)
+				}
+
 				a.App.PostFunc(func() {
+					a.UI.SetSources(states, rates)
 					b := iorate.HumanizeBytes(float64(a.UI.PagerLen()))
-					a.UI.SetMessage(fmt.Sprintf("%d/%d containers | %s transferred | %s/s | %d lps", activeCnt, allCnt, b, r, l))
+					counts := fmt.Sprintf("%d/%d containers", activeCnt, allCnt)
+					if waiting > 0 {
+						counts += fmt.Sprintf(" | %d waiting", waiting)
+					}
+					if backoff > 0 {
+						counts += fmt.Sprintf(" | %d backoff", backoff)
+					}
+					a.UI.SetMessage(fmt.Sprintf("%s | %s transferred | %s/s | %d lps", counts, b, r, l))
 				})
 			case <-ctx.Done():
 				break
@@ -114,3 +234,91 @@ func (a *App) Run(ctx context.Context) error {
 
 	return a.App.Wait()
 }
+
+// RunHeadless writes container logs to w without starting the UI. If q is
+// non-nil, only events whose structured form satisfies the query are written.
+// As in the UI, continuation lines are joined to the line they continue.
+func (a *App) RunHeadless(ctx context.Context, w io.Writer, q *query.Query) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- a.LogManager.Run(ctx)
+	}()
+
+	// Stack traces are joined as in the UI, so that the query sees whole
+	// events, and their lines are written together.
+	asm := newAssembler(joinTimeout)
+	flush := time.Tick(joinTimeout / 2)
+	write := func(evs []event) error {
+		for _, ev := range evs {
+			a.detector.Sample(ev.line.Source(), ev.Text())
+			if q != nil {
+				s, _ := a.transform(ev.line.Source(), ev.Text())
+				if !ev.line.Timestamp.IsZero() {
+					s.Timestamp = ev.line.Timestamp
+				} else if s.Timestamp.IsZero() {
+					s.Timestamp = time.Now()
+				}
+				if !q.Match(s) {
+					continue
+				}
+			}
+			if _, err := fmt.Fprintf(w, "%s%s\n", prefix(ev.line), ev.Text()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case line := <-a.LogManager.Logs():
+			if line.Type != logger.LogLineTypeContainer {
+				a.l.Printf("axe: %s", line.Text)
+				continue
+			}
+			if err := write(asm.Add(line, time.Now())); err != nil {
+				return err
+			}
+		case <-flush:
+			if err := write(asm.Flush(time.Now())); err != nil {
+				return err
+			}
+		case err := <-errCh:
+			if err != nil {
+				return err
+			}
+			return write(asm.Flush(time.Now().Add(joinTimeout)))
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// imageOf returns a function that looks up the image of the container that a
+// line came from, by its source, so that formats are detected per image.
+func imageOf(m *kubelogs.Manager) func(string) (string, bool) {
+	return func(source string) (string, bool) {
+		segs := strings.SplitN(source, "/", 3)
+		if len(segs) != 3 {
+			return "", false
+		}
+		pi, ok := m.PodInfo(segs[0], segs[1])
+		if !ok {
+			return "", false
+		}
+		for _, c := range pi.Containers {
+			if c.Name == segs[2] && c.Image != "" {
+				return c.Image, true
+			}
+		}
+		return "", false
+	}
+}
+
+// prefix returns the prefix that a container line is shown with.
+func prefix(line logger.LogLine) string {
+	if line.Late {
+		return line.Name + "] " + lateMarker
+	}
+	return line.Name + "] "
+}
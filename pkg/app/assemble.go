@@ -0,0 +1,192 @@
+package app
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ripta/axe/pkg/logger"
+)
+
+// joinTimeout is how long the last line of an event from a container is held
+// for continuation lines, before the event is shown as it is. Every line is
+// delayed by up to this long, unless the next line from its container comes
+// sooner.
+const joinTimeout = 250 * time.Millisecond
+
+var (
+	// continuationPrefixes start lines that continue the event before them,
+	// in the stack traces of Java, Python and Go.
+	continuationPrefixes = []string{
+		"Caused by:",
+		"Suppressed:",
+		"Traceback (most recent call last):",
+		"During handling of the above exception",
+		"The above exception was the direct cause",
+		"created by ",
+	}
+	// framePrefixes start the frames of Java stack traces, which are only
+	// taken as frames when unindented inside a trace, as ordinary lines may
+	// start with them too.
+	framePrefixes = []string{"at ", "... "}
+	// exceptionLine matches the lines that start Java stack traces, e.g.,
+	// "java.lang.IllegalStateException: boom" or "OutOfMemoryError".
+	exceptionLine = regexp.MustCompile(`(Exception|Throwable|\wError)\b`)
+	goroutineLine = regexp.MustCompile(`^goroutine \d+ \[`)
+	// goFrameLine matches the function calls in Go stack traces, e.g.,
+	// "main.(*Server).Serve(0xc000010000)".
+	goFrameLine = regexp.MustCompile(`^[\w.\-/*()\[\]]+\(.*\)$`)
+)
+
+// event is a logical log line, made of a first line and the lines that
+// continue it.
+type event struct {
+	line logger.LogLine
+	more []string
+}
+
+// Text returns all the lines of the event, joined by newlines.
+func (e event) Text() string {
+	if len(e.more) == 0 {
+		return e.line.Text
+	}
+	return e.line.Text + "\n" + strings.Join(e.more, "\n")
+}
+
+// pendingEvent is an event that may yet be continued.
+type pendingEvent struct {
+	event
+	last time.Time
+	// blanks holds the blank lines after the event, which are only joined to
+	// it if the line after them continues it.
+	blanks []string
+	// javaTrace, goTrace and pyTrace are set inside the stack traces of
+	// Java, Go and Python, whose frames and final lines may not be indented.
+	javaTrace bool
+	goTrace   bool
+	pyTrace   bool
+}
+
+// assembler joins the continuation lines of stack traces and the like, from
+// each container separately, into the line they continue.
+type assembler struct {
+	timeout time.Duration
+	pending map[string]*pendingEvent
+}
+
+func newAssembler(timeout time.Duration) *assembler {
+	return &assembler{
+		timeout: timeout,
+		pending: make(map[string]*pendingEvent),
+	}
+}
+
+// Add adds a container line, and returns the event that it completes by
+// starting a new event, if any.
+func (a *assembler) Add(line logger.LogLine, now time.Time) []event {
+	src := line.Source()
+	pe, ok := a.pending[src]
+	if ok && line.Text == "" {
+		// Blank lines separate the parts of Go panics and chained Python
+		// exceptions, but also unrelated lines.
+		pe.blanks = append(pe.blanks, line.Text)
+		pe.last = now
+		return nil
+	}
+	if ok && pe.continues(line.Text) {
+		pe.more = append(pe.more, pe.blanks...)
+		pe.more = append(pe.more, line.Text)
+		pe.blanks = nil
+		pe.last = now
+		return nil
+	}
+
+	a.pending[src] = &pendingEvent{event: event{line: line}, last: now}
+	a.pending[src].track(line.Text)
+	if !ok {
+		return nil
+	}
+	return []event{pe.done()}
+}
+
+// Flush returns the events whose last line was added at least the timeout
+// before now, in the order their last lines were added.
+func (a *assembler) Flush(now time.Time) []event {
+	due := make([]*pendingEvent, 0)
+	for src, pe := range a.pending {
+		if now.Sub(pe.last) >= a.timeout {
+			due = append(due, pe)
+			delete(a.pending, src)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].last.Before(due[j].last)
+	})
+
+	evs := make([]event, 0, len(due))
+	for _, pe := range due {
+		evs = append(evs, pe.done())
+	}
+	return evs
+}
+
+// continues returns whether a line continues the event, and keeps track of
+// whether the event is in a stack trace.
+func (pe *pendingEvent) continues(text string) bool {
+	cont := false
+	switch {
+	case text[0] == ' ' || text[0] == '\t':
+		cont = true
+	case goroutineLine.MatchString(text):
+		cont = true
+	case pe.goTrace && goFrameLine.MatchString(text):
+		cont = true
+	case pe.pyTrace:
+		// The exception ends the traceback, unindented.
+		cont = true
+		pe.pyTrace = false
+	}
+	for _, p := range continuationPrefixes {
+		if strings.HasPrefix(text, p) {
+			cont = true
+		}
+	}
+	for _, p := range framePrefixes {
+		if pe.inTrace() && strings.HasPrefix(text, p) {
+			cont = true
+		}
+	}
+
+	if cont {
+		pe.track(text)
+	}
+	return cont
+}
+
+// inTrace returns whether the event is in a stack trace.
+func (pe *pendingEvent) inTrace() bool {
+	return pe.javaTrace || pe.goTrace || pe.pyTrace
+}
+
+// track notes the start of a stack trace.
+func (pe *pendingEvent) track(text string) {
+	switch {
+	case exceptionLine.MatchString(text):
+		pe.javaTrace = true
+	case goroutineLine.MatchString(text):
+		pe.goTrace = true
+	case strings.HasPrefix(text, "Traceback (most recent call last):"):
+		pe.pyTrace = true
+	}
+}
+
+// done returns the event, without trailing blank lines, or the blank lines
+// after it.
+func (pe *pendingEvent) done() event {
+	ev := pe.event
+	for len(ev.more) > 0 && strings.TrimSpace(ev.more[len(ev.more)-1]) == "" {
+		ev.more = ev.more[:len(ev.more)-1]
+	}
+	return ev
+}
@@ -0,0 +1,269 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ripta/axe/pkg/logger"
+)
+
+func containerLine(pod, text string) logger.LogLine {
+	return logger.LogLine{
+		Type:      logger.LogLineTypeContainer,
+		Namespace: "ns",
+		Name:      pod,
+		Container: "c",
+		Text:      text,
+	}
+}
+
+// assemble adds the lines of a single container, and then flushes them all.
+func assemble(in string) []event {
+	a := newAssembler(time.Second)
+	now := time.Now()
+
+	evs := make([]event, 0)
+	for _, text := range strings.Split(in, "\n") {
+		evs = append(evs, a.Add(containerLine("p", text), now)...)
+	}
+	return append(evs, a.Flush(now.Add(time.Second))...)
+}
+
+func TestAssemblerJoins(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want [][]string
+	}{
+		{
+			name: "plain lines",
+			in:   "one\ntwo\nthree",
+			want: [][]string{{"one"}, {"two"}, {"three"}},
+		},
+		{
+			name: "java exception with a cause",
+			in: `Exception in thread "main" java.lang.IllegalStateException: boom
+	at com.example.Foo.bar(Foo.java:10)
+	at com.example.Main.main(Main.java:5)
+Caused by: java.io.IOException: nope
+	at com.example.Foo.io(Foo.java:20)
+	... 2 more
+INFO next`,
+			want: [][]string{
+				{
+					`Exception in thread "main" java.lang.IllegalStateException: boom`,
+					"\tat com.example.Foo.bar(Foo.java:10)",
+					"\tat com.example.Main.main(Main.java:5)",
+					"Caused by: java.io.IOException: nope",
+					"\tat com.example.Foo.io(Foo.java:20)",
+					"\t... 2 more",
+				},
+				{"INFO next"},
+			},
+		},
+		{
+			name: "unindented at in a trace",
+			in:   "java.lang.IllegalStateException: boom\nat Foo.bar(Foo.java:10)\n... 3 more\nnext",
+			want: [][]string{{"java.lang.IllegalStateException: boom", "at Foo.bar(Foo.java:10)", "... 3 more"}, {"next"}},
+		},
+		{
+			name: "unindented at outside a trace",
+			in:   "error: boom\nat startup, loading config\nnext",
+			want: [][]string{{"error: boom"}, {"at startup, loading config"}, {"next"}},
+		},
+		{
+			name: "unindented ellipsis outside a trace",
+			in:   "loading plugins\n... done",
+			want: [][]string{{"loading plugins"}, {"... done"}},
+		},
+		{
+			name: "blank line before an unrelated line",
+			in:   "Exception in thread \"main\" java.lang.RuntimeException: boom\n\tat A.b(A.java:1)\n\nINFO next",
+			want: [][]string{
+				{`Exception in thread "main" java.lang.RuntimeException: boom`, "\tat A.b(A.java:1)"},
+				{"INFO next"},
+			},
+		},
+		{
+			name: "blank line before an unindented at outside a trace",
+			in:   "ERROR boom\n\nat startup, loading config",
+			want: [][]string{{"ERROR boom"}, {"at startup, loading config"}},
+		},
+		{
+			name: "blank line before a continuation",
+			in:   "Exception in thread \"main\" java.lang.RuntimeException: boom\n\tat A.b(A.java:1)\n\n\tat A.c(A.java:2)",
+			want: [][]string{
+				{`Exception in thread "main" java.lang.RuntimeException: boom`, "\tat A.b(A.java:1)", "", "\tat A.c(A.java:2)"},
+			},
+		},
+		{
+			name: "go panic",
+			in: `panic: runtime error: index out of range
+
+goroutine 1 [running]:
+main.main()
+	/tmp/main.go:12 +0x1d
+github.com/x/y.(*T).Run(0xc000010000)
+	/tmp/y.go:3 +0x2
+created by main.start
+	/tmp/main.go:3
+{"level":"info","msg":"restarted"}`,
+			want: [][]string{
+				{
+					"panic: runtime error: index out of range",
+					"",
+					"goroutine 1 [running]:",
+					"main.main()",
+					"\t/tmp/main.go:12 +0x1d",
+					"github.com/x/y.(*T).Run(0xc000010000)",
+					"\t/tmp/y.go:3 +0x2",
+					"created by main.start",
+					"\t/tmp/main.go:3",
+				},
+				{`{"level":"info","msg":"restarted"}`},
+			},
+		},
+		{
+			name: "function calls outside go traces",
+			in:   "calling\nmain.main()",
+			want: [][]string{{"calling"}, {"main.main()"}},
+		},
+		{
+			name: "python traceback",
+			in: `ERROR failed to handle request
+Traceback (most recent call last):
+  File "app.py", line 1, in <module>
+    handle()
+ZeroDivisionError: division by zero
+INFO after`,
+			want: [][]string{
+				{
+					"ERROR failed to handle request",
+					"Traceback (most recent call last):",
+					`  File "app.py", line 1, in <module>`,
+					"    handle()",
+					"ZeroDivisionError: division by zero",
+				},
+				{"INFO after"},
+			},
+		},
+		{
+			name: "chained python exceptions",
+			in: `Traceback (most recent call last):
+  File "a.py", line 1, in <module>
+KeyError: 'x'
+
+During handling of the above exception, another exception occurred:
+
+Traceback (most recent call last):
+  File "a.py", line 3, in <module>
+ValueError: bad
+next`,
+			want: [][]string{
+				{
+					"Traceback (most recent call last):",
+					`  File "a.py", line 1, in <module>`,
+					"KeyError: 'x'",
+					"",
+					"During handling of the above exception, another exception occurred:",
+					"",
+					"Traceback (most recent call last):",
+					`  File "a.py", line 3, in <module>`,
+					"ValueError: bad",
+				},
+				{"next"},
+			},
+		},
+		{
+			name: "trailing blank lines are dropped",
+			in:   "one\n\n\ntwo",
+			want: [][]string{{"one"}, {"two"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([][]string, 0)
+			for _, ev := range assemble(tt.in) {
+				got = append(got, append([]string{ev.line.Text}, ev.more...))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssemblerHoldsUntilNextLine(t *testing.T) {
+	a := newAssembler(time.Second)
+	now := time.Now()
+
+	if evs := a.Add(containerLine("p", "first"), now); len(evs) != 0 {
+		t.Fatalf("first line was not held: %v", evs)
+	}
+	if evs := a.Add(containerLine("p", "\tcontinued"), now); len(evs) != 0 {
+		t.Fatalf("continuation line completed an event: %v", evs)
+	}
+
+	// Lines from other containers do not complete the event.
+	if evs := a.Add(containerLine("q", "other"), now); len(evs) != 0 {
+		t.Fatalf("line from another container completed an event: %v", evs)
+	}
+
+	evs := a.Add(containerLine("p", "second"), now)
+	if len(evs) != 1 || evs[0].Text() != "first\n\tcontinued" {
+		t.Fatalf("next line completed %v, want the first event", evs)
+	}
+}
+
+func TestAssemblerFlush(t *testing.T) {
+	a := newAssembler(time.Second)
+	start := time.Now()
+
+	a.Add(containerLine("p", "from p"), start)
+	a.Add(containerLine("q", "from q"), start.Add(300*time.Millisecond))
+	a.Add(containerLine("p", "\tcontinued"), start.Add(500*time.Millisecond))
+
+	if evs := a.Flush(start.Add(999 * time.Millisecond)); len(evs) != 0 {
+		t.Fatalf("flushed %v before the timeout", evs)
+	}
+
+	// The timeout runs from the last line of each event.
+	evs := a.Flush(start.Add(1300 * time.Millisecond))
+	if len(evs) != 1 || evs[0].Text() != "from q" {
+		t.Fatalf("flushed %v, want only the event from q", evs)
+	}
+
+	evs = a.Flush(start.Add(1500 * time.Millisecond))
+	if len(evs) != 1 || evs[0].Text() != "from p\n\tcontinued" {
+		t.Fatalf("flushed %v, want the event from p", evs)
+	}
+
+	// A continuation line after the flush starts a new event.
+	a.Add(containerLine("p", "\tlate"), start.Add(2*time.Second))
+	evs = a.Flush(start.Add(3 * time.Second))
+	if len(evs) != 1 || evs[0].Text() != "\tlate" || len(evs[0].more) != 0 {
+		t.Fatalf("flushed %v, want the late line on its own", evs)
+	}
+}
+
+func TestAssemblerFlushOrder(t *testing.T) {
+	a := newAssembler(time.Second)
+	start := time.Now()
+
+	pods := []string{"a", "b", "c", "d", "e"}
+	for i, pod := range pods {
+		a.Add(containerLine(pod, pod), start.Add(time.Duration(i)*time.Millisecond))
+	}
+
+	evs := a.Flush(start.Add(time.Minute))
+	got := make([]string, 0, len(evs))
+	for _, ev := range evs {
+		got = append(got, ev.line.Text)
+	}
+	if !reflect.DeepEqual(got, pods) {
+		t.Errorf("flushed %v, want %v", got, pods)
+	}
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ripta/axe/pkg/config"
+	"github.com/ripta/axe/pkg/ui/themes"
+)
+
+// themesDir is the directory under the configuration directory that holds
+// theme files.
+const themesDir = "themes"
+
+// loadTheme returns a built-in theme by name, or else reads a theme file. A
+// bare name refers to a file in the themes directory; anything that looks
+// like a path is read as-is.
+func loadTheme(name string) (themes.Theme, error) {
+	if name == "" {
+		name = themes.DefaultName
+	}
+	if t, ok := themes.Builtin(name); ok {
+		return t, nil
+	}
+
+	path := name
+	if !strings.ContainsRune(name, filepath.Separator) && filepath.Ext(name) == "" {
+		p, err := config.Path(filepath.Join(themesDir, name+".yaml"))
+		if err != nil {
+			return themes.Theme{}, err
+		}
+		path = p
+	}
+
+	t, err := themes.Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return themes.Theme{}, fmt.Errorf("unknown theme %q: not one of %s, and %s does not exist", name, strings.Join(themes.Names(), ", "), path)
+	}
+	return t, err
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"github.com/ripta/axe/pkg/config"
+	"github.com/ripta/axe/pkg/structstream"
+)
+
+// builtinFormats are tried in order on each line, after those defined by the
+// user. Lines that no format parses are passed through, so every line has a
+// structured form.
+var builtinFormats = []structstream.Format{
+	{Name: "klog", Transform: structstream.KlogTransformer},
+	{Name: "glog", Transform: structstream.GlogTransformer},
+	{Name: "json", Transform: structstream.JSONTransformer},
+	{Name: "envoy", Transform: structstream.EnvoyTransformer},
+	{Name: "access", Transform: structstream.AccessLogTransformer},
+	{Name: "zap", Transform: structstream.ZapTransformer},
+	{Name: "logfmt", Transform: structstream.LogfmtTransformer},
+}
+
+// Formats compiles the transformers defined by the user, and returns them,
+// in order, with the built-in formats after them.
+func Formats(defs []config.Transformer) ([]structstream.Format, error) {
+	fs, err := UserFormats(defs)
+	if err != nil {
+		return nil, err
+	}
+	return append(fs, builtinFormats...), nil
+}
+
+// Transformers combines the formats returned by Formats into a transformer
+// that tries each in turn, and passes through lines that none parses.
+func Transformers(defs []config.Transformer) (structstream.Transformer, error) {
+	fs, err := Formats(defs)
+	if err != nil {
+		return nil, err
+	}
+	return structstream.CombineTransformers(false, append(transformersOf(fs), structstream.PassthruTransformer)...), nil
+}
+
+// UserFormats compiles the transformers defined by the user.
+func UserFormats(defs []config.Transformer) ([]structstream.Format, error) {
+	fs := make([]structstream.Format, 0, len(defs))
+	for _, d := range defs {
+		t, err := structstream.NewRegexpTransformer(structstream.RegexpSpec{
+			Name:            d.Name,
+			Pattern:         d.Pattern,
+			TimestampGroup:  d.TimestampGroup,
+			TimestampLayout: d.TimestampLayout,
+			PriorityGroup:   d.PriorityGroup,
+			MessageGroup:    d.MessageGroup,
+		})
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, structstream.Format{Name: d.Name, Transform: t})
+	}
+	return fs, nil
+}
+
+// UserTransformers compiles the transformers defined by the user.
+func UserTransformers(defs []config.Transformer) ([]structstream.Transformer, error) {
+	fs, err := UserFormats(defs)
+	if err != nil {
+		return nil, err
+	}
+	return transformersOf(fs), nil
+}
+
+func transformersOf(fs []structstream.Format) []structstream.Transformer {
+	ts := make([]structstream.Transformer, 0, len(fs))
+	for _, f := range fs {
+		ts = append(ts, f.Transform)
+	}
+	return ts
+}
@@ -0,0 +1,34 @@
+package config
+
+// columnsFile holds the table columns chosen for each set of namespaces.
+const columnsFile = "columns.yaml"
+
+// Column is a column shown in table mode. A zero width fills the remaining
+// space on the screen.
+type Column struct {
+	Name  string `json:"name"`
+	Width int    `json:"width,omitempty"`
+}
+
+// LoadColumns returns the columns last saved for a namespace, if any.
+func LoadColumns(namespace string) ([]Column, bool, error) {
+	all := make(map[string][]Column)
+	if err := readYAML(columnsFile, &all); err != nil {
+		return nil, false, err
+	}
+
+	cols, ok := all[namespace]
+	return cols, ok, nil
+}
+
+// SaveColumns saves the columns for a namespace, keeping those of other
+// namespaces.
+func SaveColumns(namespace string, cols []Column) error {
+	all := make(map[string][]Column)
+	if err := readYAML(columnsFile, &all); err != nil {
+		return err
+	}
+
+	all[namespace] = cols
+	return writeYAML(columnsFile, all)
+}
@@ -0,0 +1,70 @@
+// Package config locates and persists axe's configuration and state files,
+// which live in $XDG_CONFIG_HOME/axe, or ~/.config/axe if it is unset.
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Dir returns the directory holding axe's configuration.
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "axe"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "axe"), nil
+}
+
+// Path returns the path of a file in the configuration directory.
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// readYAML decodes a file in the configuration directory into v. A missing
+// file leaves v untouched and is not an error.
+func readYAML(name string, v interface{}) error {
+	p, err := Path(name)
+	if err != nil {
+		return err
+	}
+
+	bs, err := ioutil.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(bs, v)
+}
+
+// writeYAML encodes v into a file in the configuration directory, creating
+// the directory if needed.
+func writeYAML(name string, v interface{}) error {
+	p, err := Path(name)
+	if err != nil {
+		return err
+	}
+
+	bs, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, bs, 0o644)
+}
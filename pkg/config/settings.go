@@ -0,0 +1,73 @@
+package config
+
+import (
+	"io/ioutil"
+
+	"sigs.k8s.io/yaml"
+)
+
+// settingsFile holds the settings that the user edits by hand.
+const settingsFile = "config.yaml"
+
+// Settings are the user's preferences.
+type Settings struct {
+	Keys Keys `json:"keys"`
+	// Theme is the name of a built-in theme, the name of a theme file in the
+	// themes directory without its extension, or the path to a theme file.
+	Theme string `json:"theme,omitempty"`
+	// Transformers parse in-house log formats, and are tried in order before
+	// the built-in transformers.
+	Transformers []Transformer `json:"transformers,omitempty"`
+}
+
+// Keys selects the key bindings. Bindings map action names to the keys
+// that run them, replacing the keys of that action in the preset; an empty
+// list unbinds the action.
+type Keys struct {
+	Preset   string              `json:"preset,omitempty"`
+	Bindings map[string][]string `json:"bindings,omitempty"`
+}
+
+// Transformer parses lines that match a regular expression, whose named
+// groups become the fields of the structured line, e.g.:
+//
+//	transformers:
+//	- name: billing
+//	  pattern: '^(?P<timestamp>\S+) (?P<priority>[A-Z]+) \[(?P<txn>\w+)\] (?P<message>.*)$'
+//	  timestampLayout: "2006-01-02T15:04:05.000Z07:00"
+//
+// The groups of the timestamp, priority and message can be renamed, and the
+// message group is required; other named groups are stored as key-values.
+type Transformer struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+
+	TimestampGroup  string `json:"timestamp,omitempty"`
+	TimestampLayout string `json:"timestampLayout,omitempty"`
+	PriorityGroup   string `json:"priority,omitempty"`
+	MessageGroup    string `json:"message,omitempty"`
+}
+
+// LoadSettings reads the user's settings. A missing file results in the
+// default settings.
+func LoadSettings() (Settings, error) {
+	var s Settings
+	if err := readYAML(settingsFile, &s); err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
+
+// ReadSettings reads settings from a file outside the configuration directory.
+func ReadSettings(path string) (Settings, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	var s Settings
+	if err := yaml.Unmarshal(bs, &s); err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
@@ -23,6 +23,35 @@ import (
 
 var ErrInformerNeverSynced = errors.New("informer cache never completed syncing")
 
+// ContainerState is the state of the log tail of a single container.
+type ContainerState string
+
+const (
+	ContainerStateWaiting    ContainerState = "waiting"
+	ContainerStateStreaming  ContainerState = "streaming"
+	ContainerStateBackoff    ContainerState = "backoff"
+	ContainerStateTerminated ContainerState = "terminated"
+)
+
+// PodInfo is a summary of a pod, for display alongside its logs.
+type PodInfo struct {
+	Node      string
+	Phase     string
+	IP        string
+	StartTime time.Time
+	Labels    map[string]string
+
+	Containers []ContainerInfo
+}
+
+// ContainerInfo is a summary of a container's status in a pod.
+type ContainerInfo struct {
+	Name     string
+	Image    string
+	Ready    bool
+	Restarts int32
+}
+
 type Manager struct {
 	kubernetes.Interface
 
@@ -31,11 +60,16 @@ type Manager struct {
 	logCh chan logger.LogLine
 	mu    sync.Mutex
 
+	// reorder is the delay for which lines are held to be reordered, or zero
+	// if lines are not reordered; lines to reorder are sent to rawCh.
+	reorder time.Duration
+	rawCh   chan logger.LogLine
+
 	nsCancelers     map[string]context.CancelFunc
 	nsInformers     map[string]informers.SharedInformerFactory
 	podLogCancelers map[string]context.CancelFunc
 
-	containerTails map[string]bool
+	containerTails map[string]ContainerState
 
 	lookback time.Duration
 	resync   time.Duration
@@ -55,8 +89,9 @@ func NewManager(l logger.Interface, cs kubernetes.Interface, lookback, resync ti
 		l:         l,
 		mu:        sync.Mutex{},
 		logCh:     make(chan logger.LogLine, 1000),
+		rawCh:     make(chan logger.LogLine, 1000),
 
-		containerTails:  make(map[string]bool),
+		containerTails:  make(map[string]ContainerState),
 		nsCancelers:     make(map[string]context.CancelFunc),
 		nsInformers:     make(map[string]informers.SharedInformerFactory),
 		podLogCancelers: make(map[string]context.CancelFunc),
@@ -66,34 +101,98 @@ func NewManager(l logger.Interface, cs kubernetes.Interface, lookback, resync ti
 	}
 }
 
+// ContainerCount returns the number of containers whose logs are streaming,
+// and of every container ever tailed. Containers that are waiting to stream,
+// or backing off, are not counted as streaming.
 func (m *Manager) ContainerCount() (int, int) {
 	var active, all int
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for _, up := range m.containerTails {
+	for _, state := range m.containerTails {
 		all += 1
-		if up {
+		if state == ContainerStateStreaming {
 			active += 1
 		}
 	}
 	return active, all
 }
 
+// Containers returns the state of every container ever tailed, keyed by
+// namespace, pod, and container name separated by slashes.
+func (m *Manager) Containers() map[string]ContainerState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cs := make(map[string]ContainerState, len(m.containerTails))
+	for key, state := range m.containerTails {
+		cs[key] = state
+	}
+	return cs
+}
+
 func (m *Manager) Logs() <-chan logger.LogLine {
 	return m.logCh
 }
 
+// SetReorderDelay makes the manager hold lines for a delay, and emit them in
+// the order they were logged across all containers, using timestamps from the
+// API server. Lines that arrive too late to be put in order are marked late.
+// It must be called before Run; a zero delay turns off reordering.
+func (m *Manager) SetReorderDelay(delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reorder = delay
+}
+
 func (m *Manager) NamespaceCount() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	return len(m.nsInformers)
 }
 
+// PodInfo returns a summary of a pod from the informer cache.
+func (m *Manager) PodInfo(ns, name string) (PodInfo, bool) {
+	m.mu.Lock()
+	inf, ok := m.nsInformers[ns]
+	m.mu.Unlock()
+	if !ok {
+		return PodInfo{}, false
+	}
+
+	pod, err := inf.Core().V1().Pods().Lister().Pods(ns).Get(name)
+	if err != nil {
+		return PodInfo{}, false
+	}
+
+	pi := PodInfo{
+		Node:   pod.Spec.NodeName,
+		Phase:  string(pod.Status.Phase),
+		IP:     pod.Status.PodIP,
+		Labels: pod.Labels,
+	}
+	if pod.Status.StartTime != nil {
+		pi.StartTime = pod.Status.StartTime.Time
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		pi.Containers = append(pi.Containers, ContainerInfo{
+			Name:     cs.Name,
+			Image:    cs.Image,
+			Ready:    cs.Ready,
+			Restarts: cs.RestartCount,
+		})
+	}
+	return pi, true
+}
+
 func (m *Manager) Run(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.reorder > 0 {
+		go newReorderer(m.reorder).run(ctx, m.rawCh, m.logCh)
+	}
+
 	for ns, inf := range m.nsInformers {
 		ctx, cancel := context.WithCancel(ctx)
 
@@ -252,19 +351,23 @@ func (m *Manager) tailPodLogs(ctx context.Context, ns, name string) {
 func (m *Manager) tailPodContainerLogs(ctx context.Context, pl listerv1.PodLister, ns, name, cn string) {
 	key := fmt.Sprintf("%s/%s/%s", ns, name, cn)
 
+	m.setContainerState(key, ContainerStateWaiting)
+	defer m.setContainerState(key, ContainerStateTerminated)
+
 	m.mu.Lock()
-	m.containerTails[key] = true
+	reorder := m.reorder > 0
 	m.mu.Unlock()
-	defer func() {
-		m.mu.Lock()
-		m.containerTails[key] = false
-		m.mu.Unlock()
-	}()
+
+	logCh := m.logCh
+	if reorder {
+		logCh = m.rawCh
+	}
 
 	m.l.Printf("starting tail of logs for container %s", key)
 	plo := v1.PodLogOptions{
-		Container: cn,
-		Follow:    true,
+		Container:  cn,
+		Follow:     true,
+		Timestamps: reorder,
 		SinceTime: &metav1.Time{
 			Time: time.Now().Add(m.lookback),
 		},
@@ -274,6 +377,7 @@ func (m *Manager) tailPodContainerLogs(ctx context.Context, pl listerv1.PodListe
 		if _, err := pl.Pods(ns).Get(name); err != nil {
 			if apierrors.IsTooManyRequests(err) {
 				// TODO(ripta): add jitter
+				m.setContainerState(key, ContainerStateBackoff)
 				time.Sleep(5 * time.Second)
 				m.l.Printf("got throttled by apiserver while asking about container %s", key)
 				continue
@@ -288,6 +392,7 @@ func (m *Manager) tailPodContainerLogs(ctx context.Context, pl listerv1.PodListe
 		stream, err := req.Context(ctx).Stream()
 		if err != nil {
 			// TODO(ripta): add jitter
+			m.setContainerState(key, ContainerStateBackoff)
 			time.Sleep(5 * time.Second)
 			m.l.Printf("could not tail container %s: %+v", key, err)
 			continue
@@ -297,8 +402,9 @@ func (m *Manager) tailPodContainerLogs(ctx context.Context, pl listerv1.PodListe
 		// lag := time.NewTimer(time.Millisecond)
 		// defer lag.Stop()
 
+		m.setContainerState(key, ContainerStateStreaming)
 		m.l.Printf("streaming logs for container %s", key)
-		m.logCh <- logger.LogLine{
+		logCh <- logger.LogLine{
 			Type: logger.LogLineTypeAxe,
 			Text: fmt.Sprintf("streaming logs for container %s", key),
 		}
@@ -309,10 +415,15 @@ func (m *Manager) tailPodContainerLogs(ctx context.Context, pl listerv1.PodListe
 				Type:      logger.LogLineTypeContainer,
 				Namespace: ns,
 				Name:      name,
+				Container: cn,
 				Text:      scanner.Text(),
 			}
+			if reorder {
+				// Lines without a timestamp are passed through as-is.
+				line.Timestamp, line.Text, _ = parseTimestamp(line.Text)
+			}
 			select {
-			case m.logCh <- line:
+			case logCh <- line:
 			// case <-lag.C:
 			// 	m.l.Printf("event buffer full, dropping logs for %s/%s", ns, name)
 			case <-ctx.Done():
@@ -328,6 +439,13 @@ func (m *Manager) tailPodContainerLogs(ctx context.Context, pl listerv1.PodListe
 		}
 
 		// TODO(ripta): add jitter
+		m.setContainerState(key, ContainerStateBackoff)
 		time.Sleep(5 * time.Second)
 	}
 }
+
+func (m *Manager) setContainerState(key string, state ContainerState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.containerTails[key] = state
+}
@@ -0,0 +1,157 @@
+package kubelogs
+
+import (
+	"container/heap"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ripta/axe/pkg/logger"
+)
+
+// minReorderTick is the shortest interval at which held lines are released.
+const minReorderTick = 10 * time.Millisecond
+
+// parseTimestamp splits the timestamp that the API server prepends to each
+// line, when asked for, from the text of the line.
+func parseTimestamp(s string) (time.Time, string, bool) {
+	i := strings.IndexByte(s, ' ')
+	if i < 0 {
+		i = len(s)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s[:i])
+	if err != nil {
+		return time.Time{}, s, false
+	}
+	if i < len(s) {
+		i++
+	}
+	return t, s[i:], true
+}
+
+// reorderer holds container lines for a delay, the watermark, and releases
+// them in timestamp order, so that lines from different containers are
+// interleaved as they were logged rather than as they were received. A line
+// that arrives after lines with later timestamps have been released is late,
+// and is released at once, out of order.
+type reorderer struct {
+	delay time.Duration
+
+	held    lineHeap
+	arrived []arrival
+	seq     int
+	// released is the timestamp of the last line released in order.
+	released time.Time
+}
+
+// arrival records when a held line is due, and its timestamp.
+type arrival struct {
+	due time.Time
+	ts  time.Time
+}
+
+type heldLine struct {
+	line logger.LogLine
+	seq  int
+}
+
+// lineHeap orders lines by timestamp, and then by arrival.
+type lineHeap []heldLine
+
+func (h lineHeap) Len() int { return len(h) }
+func (h lineHeap) Less(i, j int) bool {
+	if !h[i].line.Timestamp.Equal(h[j].line.Timestamp) {
+		return h[i].line.Timestamp.Before(h[j].line.Timestamp)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h lineHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *lineHeap) Push(x interface{}) { *h = append(*h, x.(heldLine)) }
+func (h *lineHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func newReorderer(delay time.Duration) *reorderer {
+	return &reorderer{delay: delay}
+}
+
+// run moves lines from in to out until the context is done. Lines without a
+// timestamp, like those from axe itself, are passed through at once.
+func (r *reorderer) run(ctx context.Context, in <-chan logger.LogLine, out chan<- logger.LogLine) {
+	tick := r.delay / 4
+	if tick < minReorderTick {
+		tick = minReorderTick
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	send := func(lines []logger.LogLine) bool {
+		for _, line := range lines {
+			select {
+			case out <- line:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	}
+
+	for {
+		var ready []logger.LogLine
+		select {
+		case line := <-in:
+			ready = r.add(line, time.Now())
+		case now := <-ticker.C:
+			ready = r.release(now)
+		case <-ctx.Done():
+			return
+		}
+		if !send(ready) {
+			return
+		}
+	}
+}
+
+// add holds a line, unless it must be released at once.
+func (r *reorderer) add(line logger.LogLine, now time.Time) []logger.LogLine {
+	if line.Type != logger.LogLineTypeContainer || line.Timestamp.IsZero() {
+		return []logger.LogLine{line}
+	}
+	if line.Timestamp.Before(r.released) {
+		line.Late = true
+		return []logger.LogLine{line}
+	}
+
+	r.seq++
+	heap.Push(&r.held, heldLine{line: line, seq: r.seq})
+	r.arrived = append(r.arrived, arrival{due: now.Add(r.delay), ts: line.Timestamp})
+	return nil
+}
+
+// release returns the held lines that are due, along with any lines with
+// earlier timestamps, in timestamp order.
+func (r *reorderer) release(now time.Time) []logger.LogLine {
+	var cutoff time.Time
+	n := 0
+	for ; n < len(r.arrived) && !r.arrived[n].due.After(now); n++ {
+		if r.arrived[n].ts.After(cutoff) {
+			cutoff = r.arrived[n].ts
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	r.arrived = r.arrived[n:]
+
+	lines := make([]logger.LogLine, 0)
+	for r.held.Len() > 0 && !r.held[0].line.Timestamp.After(cutoff) {
+		hl := heap.Pop(&r.held).(heldLine)
+		lines = append(lines, hl.line)
+		r.released = hl.line.Timestamp
+	}
+	return lines
+}
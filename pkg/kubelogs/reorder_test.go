@@ -0,0 +1,184 @@
+package kubelogs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ripta/axe/pkg/logger"
+)
+
+var epoch = time.Date(2020, 10, 17, 14, 32, 5, 0, time.UTC)
+
+// at returns the time ms milliseconds after the epoch.
+func at(ms int) time.Time {
+	return epoch.Add(time.Duration(ms) * time.Millisecond)
+}
+
+func timedLine(pod string, ms int) logger.LogLine {
+	return logger.LogLine{
+		Type:      logger.LogLineTypeContainer,
+		Namespace: "ns",
+		Name:      pod,
+		Container: "c",
+		Text:      at(ms).Format("05.000"),
+		Timestamp: at(ms),
+	}
+}
+
+// released describes a released line by its pod, its text, and whether it
+// is late.
+func released(lines []logger.LogLine) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		s := line.Name + " " + line.Text
+		if line.Late {
+			s += " late"
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestReordererOrder(t *testing.T) {
+	// Each step adds a line from pod, logged at ms, or if pod is empty,
+	// releases the lines that are due. Times are in milliseconds after the
+	// epoch, and the delay is 100ms.
+	type step struct {
+		now  int
+		pod  string
+		ms   int
+		want []string
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "interleaves sources",
+			steps: []step{
+				{now: 10, pod: "a", ms: 0},
+				{now: 20, pod: "a", ms: 8},
+				{now: 30, pod: "b", ms: 4},
+				{now: 40, pod: "b", ms: 12},
+				{now: 50, want: []string{}},
+				{now: 130, want: []string{"a 05.000", "b 05.004", "a 05.008"}},
+				{now: 140, want: []string{"b 05.012"}},
+			},
+		},
+		{
+			name: "releases only what is due",
+			steps: []step{
+				{now: 0, pod: "a", ms: 0},
+				{now: 50, pod: "b", ms: 50},
+				{now: 100, want: []string{"a 05.000"}},
+				{now: 149, want: []string{}},
+				{now: 150, want: []string{"b 05.050"}},
+			},
+		},
+		{
+			name: "releases earlier lines that are not yet due",
+			steps: []step{
+				{now: 0, pod: "a", ms: 10},
+				{now: 50, pod: "b", ms: 5},
+				{now: 100, want: []string{"b 05.005", "a 05.010"}},
+			},
+		},
+		{
+			name: "holds later lines that are not yet due",
+			steps: []step{
+				{now: 0, pod: "a", ms: 10},
+				{now: 50, pod: "b", ms: 20},
+				{now: 100, want: []string{"a 05.010"}},
+				{now: 150, want: []string{"b 05.020"}},
+			},
+		},
+		{
+			name: "marks lines after a release as late",
+			steps: []step{
+				{now: 0, pod: "a", ms: 10},
+				{now: 100, want: []string{"a 05.010"}},
+				{now: 110, pod: "b", ms: 5, want: []string{"b 05.005 late"}},
+				{now: 120, pod: "b", ms: 10},
+				{now: 130, pod: "b", ms: 15},
+				{now: 230, want: []string{"b 05.010", "b 05.015"}},
+			},
+		},
+		{
+			name: "keeps the order of arrival for equal timestamps",
+			steps: []step{
+				{now: 0, pod: "b", ms: 10},
+				{now: 10, pod: "a", ms: 10},
+				{now: 20, pod: "b", ms: 10},
+				{now: 120, want: []string{"b 05.010", "a 05.010", "b 05.010"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReorderer(100 * time.Millisecond)
+			for i, s := range tt.steps {
+				var got []logger.LogLine
+				if s.pod == "" {
+					got = r.release(at(s.now))
+				} else {
+					got = r.add(timedLine(s.pod, s.ms), at(s.now))
+				}
+				want := s.want
+				if want == nil {
+					want = []string{}
+				}
+				if g := released(got); !reflect.DeepEqual(g, want) {
+					t.Errorf("step %d: released %q, want %q", i, g, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReordererPassesThrough(t *testing.T) {
+	r := newReorderer(time.Second)
+	lines := []logger.LogLine{
+		{Type: logger.LogLineTypeAxe, Text: "hello"},
+		{Type: logger.LogLineTypeContainer, Name: "a", Text: "untimed"},
+	}
+	for _, line := range lines {
+		got := r.add(line, epoch)
+		if !reflect.DeepEqual(got, []logger.LogLine{line}) {
+			t.Errorf("add(%q) = %v, want it released at once", line.Text, got)
+		}
+	}
+	if got := r.release(epoch.Add(time.Hour)); len(got) != 0 {
+		t.Errorf("release = %v, want nothing held", got)
+	}
+}
+
+func TestReordererRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan logger.LogLine)
+	out := make(chan logger.LogLine)
+	go newReorderer(20*time.Millisecond).run(ctx, in, out)
+
+	in <- timedLine("a", 10)
+	in <- timedLine("b", 5)
+	in <- timedLine("a", 0)
+
+	got := make([]logger.LogLine, 0)
+	for len(got) < 3 {
+		select {
+		case line := <-out:
+			got = append(got, line)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("released %q, want 3 lines", released(got))
+		}
+	}
+	want := []string{"a 05.000", "b 05.005", "a 05.010"}
+	if g := released(got); !reflect.DeepEqual(g, want) {
+		t.Errorf("released %q, want %q", g, want)
+	}
+}
@@ -1,5 +1,7 @@
 package logger
 
+import "time"
+
 type Interface interface {
 	Printf(format string, v ...interface{})
 }
@@ -15,5 +17,19 @@ type LogLine struct {
 	Type      LogLineType
 	Namespace string
 	Name      string
+	Container string
 	Text      string
+
+	// Timestamp is when the line was logged, according to the API server,
+	// if lines are reordered; it is zero otherwise.
+	Timestamp time.Time
+	// Late is set on a line that arrived after lines logged later than it
+	// were shown, and so is shown out of order.
+	Late bool
+}
+
+// Source returns the namespace, pod, and container name of the line as a
+// single slash-separated path.
+func (l LogLine) Source() string {
+	return l.Namespace + "/" + l.Name + "/" + l.Container
 }
@@ -0,0 +1,106 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/ripta/axe/pkg/structstream"
+)
+
+var fieldAliases = map[string]string{
+	"container": "container",
+	"level":     "priority",
+	"message":   "message",
+	"meta":      "meta",
+	"msg":       "message",
+	"namespace": "namespace",
+	"ns":        "namespace",
+	"pod":       "pod",
+	"prio":      "priority",
+	"priority":  "priority",
+	"severity":  "priority",
+	"time":      "timestamp",
+	"timestamp": "timestamp",
+	"ts":        "timestamp",
+	"type":      "type",
+}
+
+// field is a reference to part of a structured line. Fields named "kv.*"
+// refer to keys in the line's KV map; nested maps are traversed on dots.
+type field struct {
+	name string
+	key  string
+}
+
+func parseField(name string) (field, bool) {
+	if strings.HasPrefix(name, "kv.") && len(name) > 3 {
+		return field{name: "kv", key: name[3:]}, true
+	}
+
+	canon, ok := fieldAliases[strings.ToLower(name)]
+	if !ok {
+		return field{}, false
+	}
+	return field{name: canon}, true
+}
+
+// resolve returns the value of the field in the line, and whether the field
+// is present at all. The namespace, pod, and container are taken from the
+// line's meta, which is expected to be in the form "ns/pod/container".
+func (f field) resolve(s structstream.Structline) (interface{}, bool) {
+	switch f.name {
+	case "kv":
+		return lookup(s.KV, f.key)
+	case "message":
+		return s.Message, true
+	case "meta":
+		return s.Meta, true
+	case "priority":
+		return s.Severity.String(), s.Severity != structstream.SeverityUnknown
+	case "timestamp":
+		return s.Timestamp, !s.Timestamp.IsZero()
+	case "type":
+		return s.Type, true
+	}
+
+	segs := strings.SplitN(s.Meta, "/", 3)
+	for i, name := range []string{"namespace", "pod", "container"} {
+		if f.name == name && i < len(segs) {
+			return segs[i], true
+		}
+	}
+	return nil, false
+}
+
+func lookup(kv map[string]interface{}, key string) (interface{}, bool) {
+	if kv == nil {
+		return nil, false
+	}
+	if v, ok := kv[key]; ok {
+		return v, true
+	}
+
+	segs := strings.SplitN(key, ".", 2)
+	if len(segs) != 2 {
+		return nil, false
+	}
+	sub, ok := kv[segs[0]].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookup(sub, segs[1])
+}
+
+// Value returns the value of a named field in the line, formatted as a
+// string. Names that are not known fields are looked up in the line's KV.
+func Value(s structstream.Structline, name string) (string, bool) {
+	f, ok := parseField(name)
+	if !ok {
+		f = field{name: "kv", key: name}
+	}
+
+	v, ok := f.resolve(s)
+	if !ok {
+		return "", false
+	}
+	return toString(v), true
+}
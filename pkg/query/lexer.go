@@ -0,0 +1,115 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	typ tokenType
+	pos int
+	val string
+}
+
+func (t token) String() string {
+	if t.typ == tokenEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.val)
+}
+
+// lex splits a query into tokens.
+func lex(in string) ([]token, error) {
+	ts := make([]token, 0)
+	rs := []rune(in)
+
+	for i := 0; i < len(rs); {
+		c := rs[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			ts = append(ts, token{typ: tokenLParen, pos: i, val: "("})
+			i++
+		case c == ')':
+			ts = append(ts, token{typ: tokenRParen, pos: i, val: ")"})
+			i++
+		case c == '&' || c == '|':
+			if i+1 >= len(rs) || rs[i+1] != c {
+				return nil, fmt.Errorf("%w at position %d: expected %q", ErrSyntax, i, string([]rune{c, c}))
+			}
+			typ := tokenAnd
+			if c == '|' {
+				typ = tokenOr
+			}
+			ts = append(ts, token{typ: typ, pos: i, val: string([]rune{c, c})})
+			i += 2
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(rs) && (rs[i+1] == '=' || rs[i+1] == '~') {
+				op += string(rs[i+1])
+			}
+			if op == "!" {
+				ts = append(ts, token{typ: tokenNot, pos: i, val: op})
+				i++
+				continue
+			}
+			if _, ok := operators[op]; !ok {
+				return nil, fmt.Errorf("%w at position %d: unknown operator %q", ErrSyntax, i, op)
+			}
+			ts = append(ts, token{typ: tokenOp, pos: i, val: op})
+			i += len(op)
+		case c == '"' || c == '\'':
+			j := i + 1
+			for ; j < len(rs) && rs[j] != c; j++ {
+				if rs[j] == '\\' && c == '"' {
+					j++
+				}
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("%w at position %d: unterminated string", ErrSyntax, i)
+			}
+
+			val := string(rs[i+1 : j])
+			if c == '"' {
+				uq, err := strconv.Unquote(string(rs[i : j+1]))
+				if err != nil {
+					return nil, fmt.Errorf("%w at position %d: %v", ErrSyntax, i, err)
+				}
+				val = uq
+			}
+			ts = append(ts, token{typ: tokenString, pos: i, val: val})
+			i = j + 1
+		case isWordRune(c):
+			j := i
+			for ; j < len(rs) && isWordRune(rs[j]); j++ {
+			}
+			ts = append(ts, token{typ: tokenWord, pos: i, val: string(rs[i:j])})
+			i = j
+		default:
+			return nil, fmt.Errorf("%w at position %d: unexpected %q", ErrSyntax, i, string(c))
+		}
+	}
+
+	return append(ts, token{typ: tokenEOF, pos: len(rs)}), nil
+}
+
+func isWordRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("_.@:-+/*", c)
+}
@@ -0,0 +1,126 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().typ == tokenOr {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &orNode{l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().typ == tokenAnd {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = &andNode{l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	switch t := p.next(); t.typ {
+	case tokenNot:
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{n: n}, nil
+	case tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.typ != tokenRParen {
+			return nil, fmt.Errorf("%w at position %d: expected \")\" but got %s", ErrSyntax, t.pos, t)
+		}
+		return n, nil
+	case tokenWord:
+		return p.parseComparison(t)
+	default:
+		return nil, fmt.Errorf("%w at position %d: expected field but got %s", ErrSyntax, t.pos, t)
+	}
+}
+
+func (p *parser) parseComparison(ft token) (node, error) {
+	f, ok := parseField(ft.val)
+	if !ok {
+		return nil, fmt.Errorf("%w at position %d: unknown field %s", ErrSyntax, ft.pos, ft)
+	}
+
+	if p.peek().typ != tokenOp {
+		return &existsNode{f: f}, nil
+	}
+
+	ot := p.next()
+	vt := p.next()
+	if vt.typ != tokenWord && vt.typ != tokenString {
+		return nil, fmt.Errorf("%w at position %d: expected value but got %s", ErrSyntax, vt.pos, vt)
+	}
+
+	n := &compareNode{
+		f:   f,
+		op:  operators[ot.val],
+		lit: vt.val,
+	}
+
+	if n.op == opMatch || n.op == opNotMatch {
+		re, err := regexp.Compile(vt.val)
+		if err != nil {
+			return nil, fmt.Errorf("%w at position %d: %v", ErrSyntax, vt.pos, err)
+		}
+		n.re = re
+	} else if f.name == "timestamp" {
+		t, ok := parseTime(vt.val)
+		if !ok {
+			return nil, fmt.Errorf("%w at position %d: expected an RFC3339 time but got %s", ErrSyntax, vt.pos, vt)
+		}
+		n.t = t
+	}
+
+	if num, err := strconv.ParseFloat(vt.val, 64); err == nil {
+		n.num = num
+		n.isN = true
+	}
+
+	return n, nil
+}
@@ -0,0 +1,235 @@
+// Package query implements a small expression language for selecting
+// structured log lines, e.g.:
+//
+//	priority>=WARNING && kv.status_code>=500 && pod=~"api-.*"
+//
+// Comparisons are joined with && and ||, negated with !, and grouped with
+// parentheses. A bare field is true when the field is present and non-empty.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ripta/axe/pkg/structstream"
+)
+
+var ErrSyntax = errors.New("syntax error")
+
+type op string
+
+const (
+	opEQ       op = "=="
+	opNE       op = "!="
+	opLT       op = "<"
+	opLE       op = "<="
+	opGT       op = ">"
+	opGE       op = ">="
+	opMatch    op = "=~"
+	opNotMatch op = "!~"
+)
+
+var operators = map[string]op{
+	"=":  opEQ,
+	"==": opEQ,
+	"!=": opNE,
+	"<":  opLT,
+	"<=": opLE,
+	">":  opGT,
+	">=": opGE,
+	"=~": opMatch,
+	"!~": opNotMatch,
+}
+
+// Query is a compiled query expression.
+type Query struct {
+	expr node
+	src  string
+}
+
+// Parse compiles a query expression.
+func Parse(in string) (*Query, error) {
+	ts, err := lex(in)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: ts}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokenEOF {
+		return nil, fmt.Errorf("%w at position %d: unexpected %s", ErrSyntax, t.pos, t)
+	}
+
+	return &Query{expr: n, src: strings.TrimSpace(in)}, nil
+}
+
+// Match reports whether the structured line satisfies the query.
+func (q *Query) Match(s structstream.Structline) bool {
+	return q.expr.eval(s)
+}
+
+func (q *Query) String() string {
+	return q.src
+}
+
+type node interface {
+	eval(structstream.Structline) bool
+}
+
+type andNode struct {
+	l, r node
+}
+
+func (n *andNode) eval(s structstream.Structline) bool {
+	return n.l.eval(s) && n.r.eval(s)
+}
+
+type orNode struct {
+	l, r node
+}
+
+func (n *orNode) eval(s structstream.Structline) bool {
+	return n.l.eval(s) || n.r.eval(s)
+}
+
+type notNode struct {
+	n node
+}
+
+func (n *notNode) eval(s structstream.Structline) bool {
+	return !n.n.eval(s)
+}
+
+type existsNode struct {
+	f field
+}
+
+func (n *existsNode) eval(s structstream.Structline) bool {
+	v, ok := n.f.resolve(s)
+	return ok && toString(v) != ""
+}
+
+type compareNode struct {
+	f   field
+	op  op
+	lit string
+	num float64
+	isN bool
+	re  *regexp.Regexp
+	t   time.Time
+}
+
+func (n *compareNode) eval(s structstream.Structline) bool {
+	v, ok := n.f.resolve(s)
+	if !ok {
+		return n.op == opNE || n.op == opNotMatch
+	}
+
+	switch n.op {
+	case opMatch:
+		return n.re.MatchString(toString(v))
+	case opNotMatch:
+		return !n.re.MatchString(toString(v))
+	}
+
+	c, ok := n.compare(v)
+	if !ok {
+		return n.op == opNE
+	}
+
+	switch n.op {
+	case opEQ:
+		return c == 0
+	case opNE:
+		return c != 0
+	case opLT:
+		return c < 0
+	case opLE:
+		return c <= 0
+	case opGT:
+		return c > 0
+	case opGE:
+		return c >= 0
+	}
+	return false
+}
+
+// compare compares a field value with the literal, using the ordering that
+// makes sense for the field: severities, timestamps, numbers, and finally
+// strings.
+func (n *compareNode) compare(v interface{}) (int, bool) {
+	if n.f.name == "priority" {
+		a := structstream.ParseSeverity(toString(v))
+		b := structstream.ParseSeverity(n.lit)
+		if a != structstream.SeverityUnknown && b != structstream.SeverityUnknown {
+			return int(a - b), true
+		}
+		return strings.Compare(strings.ToUpper(toString(v)), strings.ToUpper(n.lit)), true
+	}
+
+	if t, ok := v.(time.Time); ok {
+		switch {
+		case t.Before(n.t):
+			return -1, true
+		case t.After(n.t):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if n.isN {
+		if f, ok := toFloat(v); ok {
+			switch {
+			case f < n.num:
+				return -1, true
+			case f > n.num:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	return strings.Compare(toString(v), n.lit), true
+}
+
+// parseTime parses a literal compared with the timestamp.
+func parseTime(s string) (time.Time, bool) {
+	for _, format := range []string{time.RFC3339Nano, time.RFC3339} {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch tv := v.(type) {
+	case float64:
+		return tv, true
+	case int:
+		return float64(tv), true
+	case string:
+		f, err := strconv.ParseFloat(tv, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toString(v interface{}) string {
+	switch tv := v.(type) {
+	case string:
+		return tv
+	case nil:
+		return ""
+	case time.Time:
+		return tv.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,207 @@
+package query
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ripta/axe/pkg/structstream"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		pos int
+		msg string
+	}{
+		{``, 0, "expected field but got end of query"},
+		{`   `, 3, "expected field but got end of query"},
+		{`pod &`, 4, `expected "&&"`},
+		{`pod | type`, 4, `expected "||"`},
+		{`pod => x`, 5, `expected value but got ">"`},
+		{`pod ~ x`, 4, `unexpected "~"`},
+		{`pod == "unterminated`, 7, "unterminated string"},
+		{`pod == 'unterminated`, 7, "unterminated string"},
+		{`pod == "bad \q"`, 7, "invalid syntax"},
+		{`pod ==`, 6, "expected value but got end of query"},
+		{`pod == (x)`, 7, `expected value but got "("`},
+		{`pod == x y`, 9, `unexpected "y"`},
+		{`(pod == x`, 9, `expected ")" but got end of query`},
+		{`pod == x)`, 8, `unexpected ")"`},
+		{`nope == x`, 0, `unknown field "nope"`},
+		{`kv. == x`, 0, `unknown field "kv."`},
+		{`pod =~ "("`, 7, "missing closing )"},
+		{`time>yesterday`, 5, `expected an RFC3339 time but got "yesterday"`},
+		{`ts >= "2024-01-01 10:00"`, 6, `expected an RFC3339 time but got "2024-01-01 10:00"`},
+		{`timestamp != 2024-01-01`, 13, `expected an RFC3339 time`},
+		{`pod == x && `, 12, "expected field but got end of query"},
+		{`!`, 1, "expected field but got end of query"},
+		{`&& pod`, 0, `expected field but got "&&"`},
+		// Positions count runes, not bytes.
+		{`pod == "é" && #`, 14, `unexpected "#"`},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded", tt.in)
+			continue
+		}
+		if !errors.Is(err, ErrSyntax) {
+			t.Errorf("Parse(%q) error %q is not a syntax error", tt.in, err)
+		}
+		want := "at position " + strconv.Itoa(tt.pos) + ": "
+		if !strings.Contains(err.Error(), want) || !strings.Contains(err.Error(), tt.msg) {
+			t.Errorf("Parse(%q) error = %q, want %q and %q", tt.in, err, want, tt.msg)
+		}
+	}
+}
+
+func TestParseString(t *testing.T) {
+	q, err := Parse("  pod == x  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.String() != "pod == x" {
+		t.Errorf("String() = %q", q.String())
+	}
+}
+
+func TestMatch(t *testing.T) {
+	ts := time.Date(2020, 10, 17, 14, 32, 5, 0, time.UTC)
+	s := structstream.Structline{
+		Type:      "json",
+		Message:   "GET /healthz done",
+		Meta:      "prod/api-7f9c/server",
+		Severity:  structstream.SeverityWarning,
+		Timestamp: ts,
+		KV: map[string]interface{}{
+			"status_code": float64(503),
+			"path":        "/healthz",
+			"empty":       "",
+			"ok":          false,
+			"http": map[string]interface{}{
+				"method": "GET",
+			},
+			"dotted.key": "yes",
+		},
+	}
+
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		// Severities are ordered, and spelled in any way.
+		{`priority>=WARNING`, true},
+		{`priority>=warn`, true},
+		{`priority>WARNING`, false},
+		{`priority<ERROR`, true},
+		{`level==40`, true},
+		{`severity==WARNING`, true},
+		{`prio!=INFO`, true},
+
+		// Numbers compare as numbers, and strings as strings.
+		{`kv.status_code>=500`, true},
+		{`kv.status_code>60`, true},
+		{`kv.status_code=="503"`, true},
+		{`kv.status_code<1e3`, true},
+		{`kv.path=="/healthz"`, true},
+		{`kv.path<"/i"`, true},
+		{`kv.ok==false`, true},
+
+		// Nested and dotted keys.
+		{`kv.http.method==GET`, true},
+		{`kv.dotted.key==yes`, true},
+
+		// Timestamps compare as times.
+		{`ts>="2020-10-17T14:32:05Z"`, true},
+		{`ts>"2020-10-17T14:32:05Z"`, false},
+		{`ts<"2020-10-17T16:00:00+02:00"`, false},
+		{`ts<"2020-10-17T16:00:00.5+01:00"`, true},
+		{`ts=~"^2020-10-17T14"`, true},
+		{`ts!~"^2020-10-17T14"`, false},
+
+		// Regular expressions.
+		{`pod=~"^api-"`, true},
+		{`pod!~"^api-"`, false},
+		{`msg=~healthz`, true},
+		{`container=~'^serv'`, true},
+
+		// Fields from the meta, and aliases.
+		{`ns==prod && pod==api-7f9c && container==server`, true},
+		{`namespace==staging`, false},
+		{`type==json`, true},
+		{`meta=="prod/api-7f9c/server"`, true},
+
+		// Bare fields are true when present and non-empty.
+		{`kv.path`, true},
+		{`kv.empty`, false},
+		{`kv.missing`, false},
+		{`!kv.missing`, true},
+		{`priority`, true},
+
+		// Missing fields only satisfy negative comparisons.
+		{`kv.missing==x`, false},
+		{`kv.missing!=x`, true},
+		{`kv.missing!~x`, true},
+		{`kv.missing=~x`, false},
+		{`kv.missing<1`, false},
+
+		// && binds tighter than ||, and ! tighter than both.
+		{`type==logfmt || type==json && pod==nope`, false},
+		{`(type==logfmt || type==json) && pod=~api`, true},
+		{`type==json || kv.missing && kv.missing`, true},
+		{`!type==json || pod=~api`, true},
+		{`!(type==json || pod=~api)`, false},
+		{`!!kv.path`, true},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := q.Match(s); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMatchUnknownSeverity(t *testing.T) {
+	q, err := Parse(`priority>=DEBUG`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Match(structstream.Structline{}) {
+		t.Error("line without a severity matched")
+	}
+}
+
+func TestValue(t *testing.T) {
+	s := structstream.Structline{
+		Meta:     "ns/pod/c",
+		Severity: structstream.SeverityError,
+		KV:       map[string]interface{}{"n": float64(2), "user": "x"},
+	}
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"pod", "pod", true},
+		{"priority", "ERROR", true},
+		{"n", "2", true},
+		{"user", "x", true},
+		{"kv.user", "x", true},
+		{"missing", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Value(s, tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Value(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package structstream
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clfTimeFormat is the format of the time in Common Log Format.
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogTransformer parses access logs in Common Log Format, as written by
+// Apache and nginx, e.g.:
+//
+//	10.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.0" 200 2326
+//
+// as well as Combined Log Format, which adds the referer and user agent, and
+// the format of the nginx ingress controller, which adds the request time and
+// the upstream after those. Severity is derived from the status code.
+func AccessLogTransformer(meta, in string) (Structline, bool) {
+	s := Structline{
+		Type: "clf",
+		Meta: meta,
+		KV:   make(map[string]interface{}),
+	}
+
+	fs, ok := accessFields(in)
+	if !ok || len(fs) < 7 || !fs[3].bracketed || !fs[4].quoted {
+		return s, false
+	}
+
+	t, err := time.Parse(clfTimeFormat, fs[3].text)
+	if err != nil {
+		return s, false
+	}
+	if !s.setRequest(fs[4].text) || !s.setStatus(fs[5].text) {
+		return s, false
+	}
+	s.Timestamp = t
+	setString(s.KV, "remote_addr", fs[0].text)
+	setString(s.KV, "user", fs[2].text)
+	setNumber(s.KV, "bytes", fs[6].text, 1)
+
+	if len(fs) >= 9 && fs[7].quoted && fs[8].quoted {
+		s.Type = "combined"
+		setString(s.KV, "referer", fs[7].text)
+		setString(s.KV, "user_agent", fs[8].text)
+	}
+
+	// The nginx ingress controller adds the request length and time, the
+	// names of the upstream and its alternative, and then the address,
+	// response length, response time and status of the upstream.
+	if len(fs) >= 15 && fs[11].bracketed && fs[12].bracketed {
+		setNumber(s.KV, "latency_ms", fs[10].text, 1000)
+		setString(s.KV, "upstream_name", fs[11].text)
+		setString(s.KV, "upstream", fs[13].text)
+	}
+
+	s.Complete = true
+	return s, true
+}
+
+// EnvoyTransformer parses access logs in the default format of Envoy, e.g.:
+//
+//	[2016-04-15T20:17:00.310Z] "POST /api/v1/locations HTTP/2" 204 - 154 0 226 100 "10.0.35.28" "nsq2http" "cc21d9b0-cf5c-432b-8c7e-98aeb7988cd2" "locations" "tcp://10.0.2.1:80"
+//
+// Severity is derived from the status code. Connections proxied over TCP have
+// no request, which is logged as "- - -" or "-", and a status code of 0.
+func EnvoyTransformer(meta, in string) (Structline, bool) {
+	s := Structline{
+		Type: "envoy",
+		Meta: meta,
+		KV:   make(map[string]interface{}),
+	}
+
+	fs, ok := accessFields(in)
+	if !ok || len(fs) < 13 || !fs[0].bracketed || !fs[1].quoted {
+		return s, false
+	}
+	for _, f := range fs[8:13] {
+		if !f.quoted {
+			return s, false
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, fs[0].text)
+	if err != nil {
+		return s, false
+	}
+	if isEnvoyTCP(fs[1].text, fs[2].text) {
+		s.Message = fs[1].text
+		s.KV["status"] = float64(0)
+		s.Severity = SeverityInfo
+	} else if !s.setRequest(fs[1].text) || !s.setStatus(fs[2].text) {
+		return s, false
+	}
+	s.Timestamp = t
+	setString(s.KV, "response_flags", fs[3].text)
+	setNumber(s.KV, "bytes_received", fs[4].text, 1)
+	setNumber(s.KV, "bytes", fs[5].text, 1)
+	setNumber(s.KV, "latency_ms", fs[6].text, 1)
+	setNumber(s.KV, "upstream_latency_ms", fs[7].text, 1)
+	setString(s.KV, "forwarded_for", fs[8].text)
+	setString(s.KV, "user_agent", fs[9].text)
+	setString(s.KV, "request_id", fs[10].text)
+	setString(s.KV, "authority", fs[11].text)
+	setString(s.KV, "upstream", fs[12].text)
+
+	s.Complete = true
+	return s, true
+}
+
+// isEnvoyTCP returns whether the request and status code of an Envoy access log
+// line are those of a TCP connection.
+func isEnvoyTCP(req, status string) bool {
+	return (req == "-" || req == "- - -") && status == "0"
+}
+
+// setRequest sets the message to the request line, and stores its method,
+// path and protocol.
+func (s *Structline) setRequest(req string) bool {
+	parts := strings.Fields(req)
+	if len(parts) != 3 {
+		return false
+	}
+
+	s.Message = req
+	s.KV["method"] = parts[0]
+	s.KV["path"] = parts[1]
+	s.KV["protocol"] = parts[2]
+	return true
+}
+
+// setStatus stores the status code, and derives the severity from it: server
+// errors are errors, and client errors are warnings.
+func (s *Structline) setStatus(f string) bool {
+	code, err := strconv.Atoi(f)
+	if err != nil || code < 100 || code > 999 {
+		return false
+	}
+
+	s.KV["status"] = float64(code)
+	switch {
+	case code >= 500:
+		s.Severity = SeverityError
+	case code >= 400:
+		s.Severity = SeverityWarning
+	default:
+		s.Severity = SeverityInfo
+	}
+	return true
+}
+
+// setString stores a field, unless it is empty or "-", which access logs
+// write in place of a missing value.
+func setString(kv map[string]interface{}, key, f string) {
+	if f != "" && f != "-" {
+		kv[key] = f
+	}
+}
+
+// setNumber stores a numeric field multiplied by a scale, as a float64 like
+// the numbers of JSON lines, unless it is missing or not a number.
+func setNumber(kv map[string]interface{}, key, f string, scale float64) {
+	if n, err := strconv.ParseFloat(f, 64); err == nil {
+		kv[key] = n * scale
+	}
+}
+
+type accessField struct {
+	text      string
+	quoted    bool
+	bracketed bool
+}
+
+// accessFields splits an access log line into fields, which are separated by
+// spaces, and are either bare, quoted with backslash escapes, or bracketed.
+func accessFields(in string) ([]accessField, bool) {
+	fs := make([]accessField, 0)
+	i := 0
+	for {
+		for i < len(in) && in[i] == ' ' {
+			i++
+		}
+		if i == len(in) {
+			return fs, true
+		}
+
+		switch in[i] {
+		case '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(in) && in[j] != '"'; j++ {
+				if in[j] == '\\' && j+1 < len(in) {
+					j++
+					// nginx escapes bytes as \xHH.
+					if in[j] == 'x' && j+2 < len(in) {
+						if c, err := strconv.ParseUint(in[j+1:j+3], 16, 8); err == nil {
+							b.WriteByte(byte(c))
+							j += 2
+							continue
+						}
+					}
+				}
+				b.WriteByte(in[j])
+			}
+			if j == len(in) {
+				return nil, false
+			}
+			fs = append(fs, accessField{text: b.String(), quoted: true})
+			i = j + 1
+		case '[':
+			j := strings.IndexByte(in[i:], ']')
+			if j < 0 {
+				return nil, false
+			}
+			fs = append(fs, accessField{text: in[i+1 : i+j], bracketed: true})
+			i += j + 1
+		default:
+			j := strings.IndexByte(in[i:], ' ')
+			if j < 0 {
+				j = len(in) - i
+			}
+			fs = append(fs, accessField{text: in[i : i+j]})
+			i += j
+		}
+	}
+}
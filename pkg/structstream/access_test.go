@@ -0,0 +1,289 @@
+package structstream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAccessLogTransformer(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		ok   bool
+
+		typ      string
+		time     time.Time
+		message  string
+		severity Severity
+		kv       map[string]interface{}
+	}{
+		{
+			name:     "common log format",
+			in:       `10.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.0" 200 2326`,
+			ok:       true,
+			typ:      "clf",
+			time:     time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC),
+			message:  "GET /index.html HTTP/1.0",
+			severity: SeverityInfo,
+			kv: map[string]interface{}{
+				"remote_addr": "10.0.0.1",
+				"user":        "frank",
+				"method":      "GET",
+				"path":        "/index.html",
+				"protocol":    "HTTP/1.0",
+				"status":      float64(200),
+				"bytes":       float64(2326),
+			},
+		},
+		{
+			name:     "missing user and size",
+			in:       `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "POST /login HTTP/1.1" 404 -`,
+			ok:       true,
+			typ:      "clf",
+			time:     time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC),
+			message:  "POST /login HTTP/1.1",
+			severity: SeverityWarning,
+			kv: map[string]interface{}{
+				"remote_addr": "10.0.0.1",
+				"method":      "POST",
+				"path":        "/login",
+				"protocol":    "HTTP/1.1",
+				"status":      float64(404),
+			},
+		},
+		{
+			name:     "combined log format",
+			in:       `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "GET /a HTTP/1.1" 502 12 "https://example.com/" "curl/7.68.0"`,
+			ok:       true,
+			typ:      "combined",
+			time:     time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC),
+			message:  "GET /a HTTP/1.1",
+			severity: SeverityError,
+			kv: map[string]interface{}{
+				"remote_addr": "10.0.0.1",
+				"method":      "GET",
+				"path":        "/a",
+				"protocol":    "HTTP/1.1",
+				"status":      float64(502),
+				"bytes":       float64(12),
+				"referer":     "https://example.com/",
+				"user_agent":  "curl/7.68.0",
+			},
+		},
+		{
+			name:     "nginx ingress",
+			in:       `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "GET /a HTTP/1.1" 200 12 "-" "curl/7.68.0" 85 0.004 [default-web-80] [] 10.1.2.3:8080 12 0.004 200 abc123`,
+			ok:       true,
+			typ:      "combined",
+			time:     time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC),
+			message:  "GET /a HTTP/1.1",
+			severity: SeverityInfo,
+			kv: map[string]interface{}{
+				"remote_addr":   "10.0.0.1",
+				"method":        "GET",
+				"path":          "/a",
+				"protocol":      "HTTP/1.1",
+				"status":        float64(200),
+				"bytes":         float64(12),
+				"user_agent":    "curl/7.68.0",
+				"latency_ms":    float64(4),
+				"upstream_name": "default-web-80",
+				"upstream":      "10.1.2.3:8080",
+			},
+		},
+		{
+			name:     "nginx escapes",
+			in:       `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "GET /\x22q\x22 HTTP/1.1" 200 1 "-" "a \"quoted\" agent"`,
+			ok:       true,
+			typ:      "combined",
+			time:     time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC),
+			message:  `GET /"q" HTTP/1.1`,
+			severity: SeverityInfo,
+			kv: map[string]interface{}{
+				"remote_addr": "10.0.0.1",
+				"method":      "GET",
+				"path":        `/"q"`,
+				"protocol":    "HTTP/1.1",
+				"status":      float64(200),
+				"bytes":       float64(1),
+				"user_agent":  `a "quoted" agent`,
+			},
+		},
+		{
+			name: "bad time",
+			in:   `10.0.0.1 - - [yesterday] "GET / HTTP/1.1" 200 1`,
+		},
+		{
+			name: "bad request line",
+			in:   `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "garbage" 200 1`,
+		},
+		{
+			name: "bad status",
+			in:   `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "GET / HTTP/1.1" 0 1`,
+		},
+		{
+			name: "unterminated quote",
+			in:   `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "GET / HTTP/1.1 200 1`,
+		},
+		{
+			name: "too few fields",
+			in:   `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "GET / HTTP/1.1"`,
+		},
+		{
+			name: "plain text",
+			in:   `Starting nginx 1.25`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := AccessLogTransformer("meta", tt.in)
+			checkAccess(t, s, ok, tt.ok, tt.typ, tt.time, tt.message, tt.severity, tt.kv)
+		})
+	}
+}
+
+func TestEnvoyTransformer(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		ok   bool
+
+		time     time.Time
+		message  string
+		severity Severity
+		kv       map[string]interface{}
+	}{
+		{
+			name:     "http",
+			in:       `[2016-04-15T20:17:00.310Z] "POST /api/v1/locations HTTP/2" 204 - 154 0 226 100 "10.0.35.28" "nsq2http" "cc21d9b0-cf5c-432b-8c7e-98aeb7988cd2" "locations" "tcp://10.0.2.1:80"`,
+			ok:       true,
+			time:     time.Date(2016, 4, 15, 20, 17, 0, 310e6, time.UTC),
+			message:  "POST /api/v1/locations HTTP/2",
+			severity: SeverityInfo,
+			kv: map[string]interface{}{
+				"method":              "POST",
+				"path":                "/api/v1/locations",
+				"protocol":            "HTTP/2",
+				"status":              float64(204),
+				"bytes_received":      float64(154),
+				"bytes":               float64(0),
+				"latency_ms":          float64(226),
+				"upstream_latency_ms": float64(100),
+				"forwarded_for":       "10.0.35.28",
+				"user_agent":          "nsq2http",
+				"request_id":          "cc21d9b0-cf5c-432b-8c7e-98aeb7988cd2",
+				"authority":           "locations",
+				"upstream":            "tcp://10.0.2.1:80",
+			},
+		},
+		{
+			name:     "server error with flags",
+			in:       `[2016-04-15T20:17:00.310Z] "GET / HTTP/1.1" 503 UF 0 91 5 - "-" "curl/7.68.0" "id" "web" "-"`,
+			ok:       true,
+			time:     time.Date(2016, 4, 15, 20, 17, 0, 310e6, time.UTC),
+			message:  "GET / HTTP/1.1",
+			severity: SeverityError,
+			kv: map[string]interface{}{
+				"method":         "GET",
+				"path":           "/",
+				"protocol":       "HTTP/1.1",
+				"status":         float64(503),
+				"response_flags": "UF",
+				"bytes_received": float64(0),
+				"bytes":          float64(91),
+				"latency_ms":     float64(5),
+				"user_agent":     "curl/7.68.0",
+				"request_id":     "id",
+				"authority":      "web",
+			},
+		},
+		{
+			name:     "tcp connection",
+			in:       `[2016-04-15T20:17:00.310Z] "- - -" 0 - 1024 2048 1500 - "-" "-" "-" "-" "10.0.2.1:5432"`,
+			ok:       true,
+			time:     time.Date(2016, 4, 15, 20, 17, 0, 310e6, time.UTC),
+			message:  "- - -",
+			severity: SeverityInfo,
+			kv: map[string]interface{}{
+				"status":         float64(0),
+				"bytes_received": float64(1024),
+				"bytes":          float64(2048),
+				"latency_ms":     float64(1500),
+				"upstream":       "10.0.2.1:5432",
+			},
+		},
+		{
+			name:     "tcp connection with a bare dash",
+			in:       `[2016-04-15T20:17:00.310Z] "-" 0 UF 0 0 1 - "-" "-" "-" "-" "-"`,
+			ok:       true,
+			time:     time.Date(2016, 4, 15, 20, 17, 0, 310e6, time.UTC),
+			message:  "-",
+			severity: SeverityInfo,
+			kv: map[string]interface{}{
+				"status":         float64(0),
+				"response_flags": "UF",
+				"bytes_received": float64(0),
+				"bytes":          float64(0),
+				"latency_ms":     float64(1),
+			},
+		},
+		{
+			name: "no request with an http status",
+			in:   `[2016-04-15T20:17:00.310Z] "-" 200 - 0 0 1 - "-" "-" "-" "-" "-"`,
+		},
+		{
+			name: "request with a zero status",
+			in:   `[2016-04-15T20:17:00.310Z] "GET / HTTP/1.1" 0 - 0 0 1 - "-" "-" "-" "-" "-"`,
+		},
+		{
+			name: "unquoted trailing field",
+			in:   `[2016-04-15T20:17:00.310Z] "GET / HTTP/1.1" 200 - 0 0 1 - "-" "-" "-" "-" upstream`,
+		},
+		{
+			name: "bad time",
+			in:   `[yesterday] "GET / HTTP/1.1" 200 - 0 0 1 - "-" "-" "-" "-" "-"`,
+		},
+		{
+			name: "common log format",
+			in:   `10.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.0" 200 2326`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := EnvoyTransformer("meta", tt.in)
+			checkAccess(t, s, ok, tt.ok, "envoy", tt.time, tt.message, tt.severity, tt.kv)
+		})
+	}
+}
+
+func checkAccess(t *testing.T, s Structline, ok, wantOK bool, typ string, ts time.Time, message string, severity Severity, kv map[string]interface{}) {
+	t.Helper()
+	if ok != wantOK {
+		t.Fatalf("ok = %v, want %v", ok, wantOK)
+	}
+	if !ok {
+		return
+	}
+
+	if !s.Complete {
+		t.Errorf("line is not complete")
+	}
+	if s.Type != typ {
+		t.Errorf("type = %q, want %q", s.Type, typ)
+	}
+	if !s.Timestamp.Equal(ts) {
+		t.Errorf("timestamp = %v, want %v", s.Timestamp, ts)
+	}
+	if s.Message != message {
+		t.Errorf("message = %q, want %q", s.Message, message)
+	}
+	if s.Severity != severity {
+		t.Errorf("severity = %v, want %v", s.Severity, severity)
+	}
+	if !reflect.DeepEqual(s.KV, kv) {
+		t.Errorf("kv = %#v, want %#v", s.KV, kv)
+	}
+}
@@ -0,0 +1,332 @@
+package structstream
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// minConfidence is the fraction of sampled lines that a format must parse to
+// be chosen for a source.
+const minConfidence = 0.6
+
+// PassthruFormat is the name of the format of lines that are not parsed. It
+// is chosen for sources whose sampled lines no format parsed.
+const PassthruFormat = "passthru"
+
+// Format is a named transformer, for a log format that a detector can choose.
+type Format struct {
+	Name      string
+	Transform Transformer
+}
+
+// Detection is the format detected for a source.
+type Detection struct {
+	// Format is the name of the format that parsed the most sampled lines.
+	// It is empty until enough lines are sampled. Formats that are tried
+	// before it, and only parsed lines that it also parsed, are more specific
+	// forms of it, and are tried first; their names are joined to its name
+	// by "+", e.g., "klog+glog".
+	Format string
+	// Confidence is the fraction of sampled lines that the format parsed,
+	// counting incomplete lines as half. Below minConfidence, every format
+	// is still tried on each line.
+	Confidence float64
+	// Override is set if the format was chosen by the user.
+	Override bool
+	// Sampled is the number of lines sampled.
+	Sampled int
+}
+
+// Chosen returns whether lines are only parsed by the format.
+func (d Detection) Chosen() bool {
+	return d.Override || d.Format != "" && d.Confidence >= minConfidence
+}
+
+// String returns the format and how it was chosen, for display.
+func (d Detection) String() string {
+	switch {
+	case d.Override:
+		return d.Format + " (set by user)"
+	case d.Format == "":
+		return fmt.Sprintf("detecting (%d lines sampled)", d.Sampled)
+	case !d.Chosen():
+		return fmt.Sprintf("mixed (best %s, %.0f%%)", d.Format, d.Confidence*100)
+	}
+	return fmt.Sprintf("%s (%.0f%%)", d.Format, d.Confidence*100)
+}
+
+type detection struct {
+	Detection
+	transform Transformer
+	done      bool
+	scores    []float64
+	// within[i][j] is whether every sampled line that format i parsed was
+	// also parsed by format j.
+	within [][]bool
+}
+
+func newDetection(n int) *detection {
+	within := make([][]bool, n)
+	for i := range within {
+		within[i] = make([]bool, n)
+		for j := range within[i] {
+			within[i][j] = true
+		}
+	}
+	return &detection{scores: make([]float64, n), within: within}
+}
+
+// Detector samples the first lines of each source, and chooses the format
+// that parses most of them, so that later lines are only parsed by it, and
+// lines that it does not parse are passed through. Choices are cached by a
+// key, such as the image of a container, so that sources with the same key
+// share a choice.
+type Detector struct {
+	mu       sync.Mutex
+	formats  []Format
+	combined Transformer
+	samples  int
+	key      func(meta string) (string, bool)
+
+	// keys caches the keys found for metas, which do not change.
+	keys       map[string]string
+	detections map[string]*detection
+}
+
+// NewDetector creates a detector that samples a number of lines of each
+// source. The key function maps the meta of a line to the key that its
+// detection is cached by. Until it finds the key of a source, the lines of
+// the source are parsed by every format, and are not sampled, so that samples
+// are not split between keys. Without a key function, the meta itself is the
+// key.
+func NewDetector(formats []Format, samples int, key func(meta string) (string, bool)) *Detector {
+	ts := make([]Transformer, 0, len(formats)+1)
+	for _, f := range formats {
+		ts = append(ts, f.Transform)
+	}
+	ts = append(ts, PassthruTransformer)
+
+	return &Detector{
+		formats:    formats,
+		combined:   CombineTransformers(false, ts...),
+		samples:    samples,
+		key:        key,
+		keys:       make(map[string]string),
+		detections: make(map[string]*detection),
+	}
+}
+
+// Formats returns the names of the formats, in the order they are tried, and
+// then PassthruFormat.
+func (d *Detector) Formats() []string {
+	names := make([]string, 0, len(d.formats)+1)
+	for _, f := range d.formats {
+		names = append(names, f.Name)
+	}
+	return append(names, PassthruFormat)
+}
+
+// Detection returns the format detected for a source, if any of its lines
+// were sampled, or the format was chosen by the user.
+func (d *Detector) Detection(meta string) (Detection, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, ok := d.keyFor(meta)
+	if !ok {
+		return Detection{}, false
+	}
+	det, ok := d.detections[key]
+	if !ok {
+		return Detection{}, false
+	}
+	return det.Detection, true
+}
+
+// SameKey returns whether the detections of two sources are cached by the
+// same key, and so share a format.
+func (d *Detector) SameKey(a, b string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ka, ok := d.keyFor(a)
+	if !ok {
+		return a == b
+	}
+	kb, ok := d.keyFor(b)
+	return ok && ka == kb
+}
+
+// Override chooses the format of a source, and of every source with the same
+// key, by name. An empty name detects the format again. It is an error if the
+// key of the source is not yet known.
+func (d *Detector) Override(meta, name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, ok := d.keyFor(meta)
+	if !ok {
+		return fmt.Errorf("the image of %s is not known yet", meta)
+	}
+	if name == "" {
+		delete(d.detections, key)
+		return nil
+	}
+
+	transform, ok := d.format(name)
+	if !ok {
+		return fmt.Errorf("unknown format %q; formats are %s", name, strings.Join(d.Formats(), ", "))
+	}
+	d.detections[key] = &detection{
+		Detection: Detection{Format: name, Override: true},
+		transform: transform,
+		done:      true,
+	}
+	return nil
+}
+
+// Sample scores a line of a source against every format, until enough lines
+// of the source are sampled to choose one. It must be called once for each
+// line, e.g., as it is appended to a buffer, and not each time the line is
+// parsed, since parsed lines may be parsed again when they are read.
+func (d *Detector) Sample(meta, in string) {
+	d.mu.Lock()
+	key, ok := d.keyFor(meta)
+	if !ok {
+		d.mu.Unlock()
+		return
+	}
+	det, ok := d.detections[key]
+	if !ok {
+		det = newDetection(len(d.formats))
+		d.detections[key] = det
+	}
+	done := det.done
+	d.mu.Unlock()
+
+	if done {
+		return
+	}
+
+	scores := make([]float64, len(d.formats))
+	for i, f := range d.formats {
+		s, ok := f.Transform(meta, in)
+		if !ok {
+			continue
+		}
+		scores[i] = 0.5
+		if s.Complete {
+			scores[i] = 1
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// The detection may have been replaced by an override in the meantime.
+	if d.detections[key] == det && !det.done {
+		det.record(scores, d.formats, d.samples)
+	}
+}
+
+// Transform is a transformer that parses a line with the format chosen for
+// its source, or until one is chosen, with every format. It does not sample
+// the line.
+func (d *Detector) Transform(meta, in string) (Structline, bool) {
+	d.mu.Lock()
+	var transform Transformer
+	if key, ok := d.keyFor(meta); ok {
+		if det, ok := d.detections[key]; ok && det.done {
+			transform = det.transform
+		}
+	}
+	d.mu.Unlock()
+
+	if transform == nil {
+		return d.combined(meta, in)
+	}
+	if s, ok := transform(meta, in); ok {
+		return s, true
+	}
+	return PassthruTransformer(meta, in)
+}
+
+func (d *Detector) format(name string) (Transformer, bool) {
+	if name == PassthruFormat {
+		return PassthruTransformer, true
+	}
+	for _, f := range d.formats {
+		if f.Name == name {
+			return f.Transform, true
+		}
+	}
+	return nil, false
+}
+
+// keyFor returns the key of a source, if it is known.
+func (d *Detector) keyFor(meta string) (string, bool) {
+	if d.key == nil {
+		return meta, true
+	}
+	if key, ok := d.keys[meta]; ok {
+		return key, true
+	}
+	key, ok := d.key(meta)
+	if ok {
+		d.keys[meta] = key
+	}
+	return key, ok
+}
+
+// record adds the scores of a sampled line, and updates the best format. Once
+// enough lines are sampled, the best format is chosen if it is good enough,
+// along with the more specific formats before it.
+func (det *detection) record(scores []float64, formats []Format, samples int) {
+	det.Sampled++
+	best := -1
+	for i, s := range scores {
+		det.scores[i] += s
+		if det.scores[i] > 0 && (best < 0 || det.scores[i] > det.scores[best]) {
+			best = i
+		}
+		if s == 0 {
+			continue
+		}
+		for j, sj := range scores {
+			if sj == 0 {
+				det.within[i][j] = false
+			}
+		}
+	}
+
+	if det.Sampled < samples {
+		return
+	}
+	det.done = true
+	if best < 0 {
+		det.Format, det.Confidence = PassthruFormat, 1
+		det.transform = PassthruTransformer
+	} else {
+		names := make([]string, 0)
+		ts := make([]Transformer, 0)
+		for i := 0; i < best; i++ {
+			if det.scores[i] > 0 && det.within[i][best] {
+				names = append(names, formats[i].Name)
+				ts = append(ts, formats[i].Transform)
+			}
+		}
+		names = append(names, formats[best].Name)
+		ts = append(ts, formats[best].Transform)
+
+		det.Format = strings.Join(names, "+")
+		det.Confidence = det.scores[best] / float64(det.Sampled)
+		if det.Chosen() {
+			det.transform = ts[0]
+			if len(ts) > 1 {
+				det.transform = CombineTransformers(false, ts...)
+			}
+		}
+	}
+	det.scores = nil
+	det.within = nil
+}
@@ -0,0 +1,305 @@
+package structstream
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testFormats = []Format{
+	{Name: "klog", Transform: KlogTransformer},
+	{Name: "glog", Transform: GlogTransformer},
+	{Name: "json", Transform: JSONTransformer},
+	{Name: "envoy", Transform: EnvoyTransformer},
+	{Name: "access", Transform: AccessLogTransformer},
+	{Name: "zap", Transform: ZapTransformer},
+	{Name: "logfmt", Transform: LogfmtTransformer},
+}
+
+const (
+	klogLine = `I1017 14:32:05.123456   12345 controller.go:42] "Synced pod" pod="kube-system/dns" attempt=2`
+	glogLine = `I1017 14:32:05.123456   12345 controller.go:43] Synced 3 pods`
+	jsonLine = `{"level":"info","msg":"hello","ts":"2020-10-17T14:32:05Z"}`
+)
+
+// sample feeds a detector lines from a source, cycling through them.
+func sample(d *Detector, meta string, n int, lines ...string) {
+	for i := 0; i < n; i++ {
+		d.Sample(meta, lines[i%len(lines)])
+	}
+}
+
+func TestDetectorChoice(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		format string
+		chosen bool
+	}{
+		{
+			name:   "json",
+			lines:  []string{jsonLine},
+			format: "json",
+			chosen: true,
+		},
+		{
+			name:   "mostly json",
+			lines:  []string{jsonLine, jsonLine, jsonLine, "plain text"},
+			format: "json",
+			chosen: true,
+		},
+		{
+			name:   "plain text",
+			lines:  []string{"plain text", "more plain text"},
+			format: PassthruFormat,
+			chosen: true,
+		},
+		{
+			name:   "klog only",
+			lines:  []string{klogLine},
+			format: "klog",
+			chosen: true,
+		},
+		{
+			name:   "glog only",
+			lines:  []string{glogLine},
+			format: "glog",
+			chosen: true,
+		},
+		{
+			name:   "klog mixed with glog",
+			lines:  []string{klogLine, glogLine, glogLine},
+			format: "klog+glog",
+			chosen: true,
+		},
+		{
+			name:   "no format good enough",
+			lines:  []string{jsonLine, glogLine, "plain", "text"},
+			format: "json",
+			chosen: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDetector(testFormats, 12, nil)
+
+			sample(d, "ns/pod/c", 11, tt.lines...)
+			det, ok := d.Detection("ns/pod/c")
+			if !ok || det.Format != "" || det.Sampled != 11 {
+				t.Fatalf("detection before enough samples = %+v, %v", det, ok)
+			}
+
+			sample(d, "ns/pod/c", 1, tt.lines...)
+			det, _ = d.Detection("ns/pod/c")
+			if det.Format != tt.format || det.Chosen() != tt.chosen {
+				t.Errorf("detection = %s, want %s (chosen %v)", det, tt.format, tt.chosen)
+			}
+		})
+	}
+}
+
+func TestDetectorSamplesOncePerLine(t *testing.T) {
+	d := NewDetector(testFormats, 50, nil)
+	buf, err := New(100, d.Transform)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines are parsed again whenever they are read and not cached, which
+	// must not count them again.
+	for i := 0; i < 30; i++ {
+		line := fmt.Sprintf("level=info msg=line%d", i)
+		d.Sample("m", line)
+		idx := buf.Append("m", line)
+		for j := 0; j < 3; j++ {
+			buf.GetAt(idx)
+			d.Transform("m", line)
+		}
+	}
+	buf.Reparse()
+	buf.GetRange(0, buf.Len()-1)
+
+	det, ok := d.Detection("m")
+	if !ok || det.Sampled != 30 || det.Format != "" {
+		t.Errorf("detection after 30 lines read repeatedly = %+v, %v", det, ok)
+	}
+}
+
+func TestDetectorOverrideWhileParsing(t *testing.T) {
+	d := NewDetector(testFormats, 2, nil)
+	sample(d, "m", 2, jsonLine)
+
+	// The first parse is held until the format is overridden and the buffer
+	// is reparsed, as if a line were being appended at the same time.
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	buf, err := New(10, func(meta, in string) (Structline, bool) {
+		s, ok := d.Transform(meta, in)
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+		return s, ok
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := buf.Append("m", jsonLine)
+	parsed := make(chan struct{})
+	go func() {
+		buf.GetAt(idx)
+		close(parsed)
+	}()
+
+	<-started
+	if err := d.Override("m", "logfmt"); err != nil {
+		t.Fatal(err)
+	}
+	reparsed := make(chan struct{})
+	go func() {
+		buf.Reparse()
+		close(reparsed)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	<-parsed
+	<-reparsed
+
+	// Lines are cached asynchronously.
+	time.Sleep(10 * time.Millisecond)
+	if s := buf.GetAt(idx); s.Type == "json" {
+		t.Errorf("line was parsed with the format before the override")
+	}
+}
+
+func TestDetectorKlogKeepsPairs(t *testing.T) {
+	d := NewDetector(testFormats, 10, nil)
+	sample(d, "m", 10, klogLine, glogLine, glogLine, glogLine)
+
+	s, _ := d.Transform("m", klogLine)
+	if s.Type != "klog" || s.KV["pod"] != "kube-system/dns" {
+		t.Errorf("structured klog line parsed as %s with kv %v", s.Type, s.KV)
+	}
+	s, _ = d.Transform("m", glogLine)
+	if s.Type != "glog" || s.Message != "Synced 3 pods" {
+		t.Errorf("unstructured klog line parsed as %s with message %q", s.Type, s.Message)
+	}
+}
+
+func TestDetectorChosenFormatOnly(t *testing.T) {
+	d := NewDetector(testFormats, 4, nil)
+	sample(d, "m", 4, jsonLine)
+
+	// A logfmt line is passed through, rather than parsed by another format.
+	s, ok := d.Transform("m", "level=info msg=x")
+	if !ok || s.Type != "passthru" {
+		t.Errorf("line in another format parsed as %s, %v", s.Type, ok)
+	}
+
+	// Below the minimum confidence, every format is still tried.
+	d = NewDetector(testFormats, 4, nil)
+	sample(d, "m", 4, jsonLine, "a", "b", "c")
+	if s, _ := d.Transform("m", "level=info msg=x"); s.Type != "logfmt" {
+		t.Errorf("line parsed as %s without a chosen format", s.Type)
+	}
+}
+
+func TestDetectorKeys(t *testing.T) {
+	images := map[string]string{}
+	d := NewDetector(testFormats, 3, func(meta string) (string, bool) {
+		image, ok := images[meta]
+		return image, ok
+	})
+
+	// Lines are parsed, but not sampled, until the image is known.
+	d.Sample("ns/a/c", jsonLine)
+	s, ok := d.Transform("ns/a/c", jsonLine)
+	if !ok || s.Type != "json" {
+		t.Fatalf("line parsed as %s, %v", s.Type, ok)
+	}
+	if _, ok := d.Detection("ns/a/c"); ok {
+		t.Fatal("line was sampled before the image was known")
+	}
+	if err := d.Override("ns/a/c", "json"); err == nil {
+		t.Error("format was set before the image was known")
+	}
+	if !d.SameKey("ns/a/c", "ns/a/c") || d.SameKey("ns/a/c", "ns/b/c") {
+		t.Error("sources without images share keys")
+	}
+
+	// Sources with the same image share samples, and the choice.
+	images["ns/a/c"] = "app:1"
+	images["ns/b/c"] = "app:1"
+	images["ns/x/c"] = "other:1"
+	sample(d, "ns/a/c", 2, jsonLine)
+	sample(d, "ns/b/c", 1, jsonLine)
+	for _, meta := range []string{"ns/a/c", "ns/b/c"} {
+		det, _ := d.Detection(meta)
+		if det.Format != "json" || det.Sampled != 3 {
+			t.Errorf("detection of %s = %+v", meta, det)
+		}
+	}
+	if !d.SameKey("ns/a/c", "ns/b/c") || d.SameKey("ns/a/c", "ns/x/c") {
+		t.Error("keys are not shared by image")
+	}
+	if _, ok := d.Detection("ns/x/c"); ok {
+		t.Error("detection of another image was shared")
+	}
+}
+
+func TestDetectorOverride(t *testing.T) {
+	d := NewDetector(testFormats, 2, nil)
+	sample(d, "m", 2, jsonLine)
+
+	if err := d.Override("m", "nope"); err == nil {
+		t.Error("unknown format was set")
+	}
+
+	if err := d.Override("m", "logfmt"); err != nil {
+		t.Fatal(err)
+	}
+	det, _ := d.Detection("m")
+	if !det.Override || det.Format != "logfmt" || !det.Chosen() {
+		t.Errorf("detection after override = %+v", det)
+	}
+	if s, _ := d.Transform("m", "level=info msg=x"); s.Type != "logfmt" {
+		t.Errorf("line parsed as %s after override", s.Type)
+	}
+	if s, _ := d.Transform("m", jsonLine); s.Type != "passthru" {
+		t.Errorf("line in the detected format parsed as %s after override", s.Type)
+	}
+
+	if err := d.Override("m", PassthruFormat); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := d.Transform("m", "level=info msg=x"); s.Type != "passthru" {
+		t.Errorf("line parsed as %s with the passthru format", s.Type)
+	}
+
+	// An empty format detects it again.
+	if err := d.Override("m", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.Detection("m"); ok {
+		t.Error("detection was not reset")
+	}
+	sample(d, "m", 2, "level=info msg=x")
+	if det, _ := d.Detection("m"); det.Format != "logfmt" || det.Override {
+		t.Errorf("detection after reset = %+v", det)
+	}
+}
+
+func ExampleDetection_String() {
+	fmt.Println(Detection{Sampled: 3})
+	fmt.Println(Detection{Format: "json", Confidence: 0.8})
+	fmt.Println(Detection{Format: "json", Confidence: 0.4})
+	fmt.Println(Detection{Format: "logfmt", Override: true})
+	// Output:
+	// detecting (3 lines sampled)
+	// json (80%)
+	// mixed (best json, 40%)
+	// logfmt (set by user)
+}
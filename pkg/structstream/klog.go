@@ -0,0 +1,37 @@
+package structstream
+
+import (
+	"strconv"
+	"strings"
+)
+
+// KlogTransformer parses the structured lines of klog v2, which follow the
+// glog header with a quoted message and key=value pairs, e.g.:
+//
+//	I1017 14:32:05.123456   12345 controller.go:42] "Synced pod" pod="kube-system/dns" attempt=2
+//
+// Lines with an unquoted message are left to GlogTransformer.
+func KlogTransformer(meta, in string) (Structline, bool) {
+	s, ok := GlogTransformer(meta, in)
+	if !ok || !s.Complete || !strings.HasPrefix(s.Message, `"`) {
+		return s, false
+	}
+	s.Type = "klog"
+
+	end, err := quotedEnd(s.Message, 0)
+	if err != nil {
+		return s, false
+	}
+	msg, err := strconv.Unquote(s.Message[:end])
+	if err != nil {
+		return s, false
+	}
+	pairs, err := parseLogfmt(s.Message[end:])
+	if err != nil {
+		return s, false
+	}
+
+	s.Message = msg
+	setPairs(s.KV, pairs)
+	return s, true
+}
@@ -0,0 +1,143 @@
+package structstream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKlogTransformer(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		ok   bool
+
+		message  string
+		severity Severity
+		kv       map[string]interface{}
+	}{
+		{
+			name:     "quoted and unquoted values",
+			in:       `I1017 14:32:05.123456   12345 controller.go:42] "Synced pod" pod="kube-system/dns" attempt=2`,
+			ok:       true,
+			message:  "Synced pod",
+			severity: SeverityInfo,
+			kv: map[string]interface{}{
+				"pid":      "12345",
+				"fileline": "controller.go:42",
+				"pod":      "kube-system/dns",
+				"attempt":  "2",
+			},
+		},
+		{
+			name:     "escapes in the message and values",
+			in:       `W1017 14:32:05.123456   12345 node.go:7] "Node \"a\" is slow" reason="took\t2s"`,
+			ok:       true,
+			message:  `Node "a" is slow`,
+			severity: SeverityWarning,
+			kv: map[string]interface{}{
+				"pid":      "12345",
+				"fileline": "node.go:7",
+				"reason":   "took\t2s",
+			},
+		},
+		{
+			name:     "quoted message without pairs",
+			in:       `E1017 14:32:05.123456   12345 main.go:1] "Failed to start"`,
+			ok:       true,
+			message:  "Failed to start",
+			severity: SeverityError,
+			kv: map[string]interface{}{
+				"pid":      "12345",
+				"fileline": "main.go:1",
+			},
+		},
+		{
+			name:     "fatal",
+			in:       `F1017 14:32:05.123456   12345 main.go:1] "Exiting" code=1`,
+			ok:       true,
+			message:  "Exiting",
+			severity: SeverityFatal,
+			kv: map[string]interface{}{
+				"pid":      "12345",
+				"fileline": "main.go:1",
+				"code":     "1",
+			},
+		},
+		{
+			name: "unquoted message is left to glog",
+			in:   `I1017 14:32:05.123456   12345 controller.go:42] Synced pod kube-system/dns`,
+		},
+		{
+			name: "unterminated message",
+			in:   `I1017 14:32:05.123456   12345 controller.go:42] "Synced pod`,
+		},
+		{
+			name: "malformed pairs",
+			in:   `I1017 14:32:05.123456   12345 controller.go:42] "Synced pod" pod="never closed`,
+		},
+		{
+			name: "plain text",
+			in:   `Starting server on port 8080`,
+		},
+		{
+			name: "empty line",
+			in:   ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := KlogTransformer("meta", tt.in)
+			if ok != tt.ok {
+				t.Fatalf("KlogTransformer(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+
+			if !s.Complete {
+				t.Errorf("line is not complete")
+			}
+			if s.Type != "klog" {
+				t.Errorf("type = %q, want %q", s.Type, "klog")
+			}
+			if s.Message != tt.message {
+				t.Errorf("message = %q, want %q", s.Message, tt.message)
+			}
+			if s.Severity != tt.severity {
+				t.Errorf("severity = %v, want %v", s.Severity, tt.severity)
+			}
+			if !reflect.DeepEqual(s.KV, tt.kv) {
+				t.Errorf("kv = %#v, want %#v", s.KV, tt.kv)
+			}
+		})
+	}
+}
+
+func TestKlogTransformerFallback(t *testing.T) {
+	in := `I1017 14:32:05.123456   12345 controller.go:42] Synced pod kube-system/dns`
+	tr := CombineTransformers(false, KlogTransformer, GlogTransformer)
+
+	s, ok := tr("", in)
+	if !ok {
+		t.Fatal("line was rejected")
+	}
+	if s.Type != "glog" {
+		t.Errorf("type = %q, want %q", s.Type, "glog")
+	}
+	if want := "Synced pod kube-system/dns"; s.Message != want {
+		t.Errorf("message = %q, want %q", s.Message, want)
+	}
+}
+
+func TestKlogTransformerTimestamp(t *testing.T) {
+	s, ok := KlogTransformer("", `I20201017 14:32:05.500000   12345 main.go:1] "Started"`)
+	if !ok {
+		t.Fatal("line was rejected")
+	}
+	want := time.Date(2020, 10, 17, 14, 32, 5, 5e8, time.UTC)
+	if !s.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", s.Timestamp, want)
+	}
+}
@@ -0,0 +1,165 @@
+package structstream
+
+import (
+	"errors"
+	"strconv"
+)
+
+// LogfmtTransformer parses lines of space-separated key=value pairs, as
+// written by go-kit and logrus, e.g.:
+//
+//	level=info ts=2020-10-17T14:32:05Z msg="listening on :8080" port=8080
+//
+// Values may be double-quoted, with Go escapes; keys without a value are
+// stored as true. The level, lvl or severity, msg, and ts or time keys are
+// moved into their own fields. As most plain text would pass for bare keys,
+// only lines that assign at least one of those keys are accepted, and lines
+// with more bare keys than assigned ones, such as "hello world msg=x", are
+// taken to be prose and rejected.
+func LogfmtTransformer(meta, in string) (Structline, bool) {
+	s := Structline{
+		Type: "logfmt",
+		Meta: meta,
+		KV:   make(map[string]interface{}),
+	}
+
+	pairs, err := parseLogfmt(in)
+	if err != nil {
+		return s, false
+	}
+	bare := 0
+	for _, p := range pairs {
+		if p.bare {
+			bare++
+		}
+	}
+	if bare*2 > len(pairs) {
+		return s, false
+	}
+
+	setPairs(s.KV, pairs)
+
+	known := false
+	tryFields(s.KV, []string{"ts", "time"}, func(vs string) bool {
+		if t, ok := parseTime(vs); ok {
+			s.Timestamp = t
+			known = true
+			return true
+		}
+		return false
+	})
+	tryFields(s.KV, []string{"level", "lvl", "severity"}, func(vs string) bool {
+		s.Severity = ParseSeverity(vs)
+		known = true
+		return true
+	})
+	tryFields(s.KV, []string{"msg"}, func(vs string) bool {
+		s.Message = vs
+		known = true
+		return true
+	})
+	if !known {
+		return s, false
+	}
+
+	s.Complete = true
+	return s, true
+}
+
+type logfmtPair struct {
+	key   string
+	value string
+	bare  bool
+}
+
+var (
+	errLogfmtKey   = errors.New("expected a key")
+	errLogfmtQuote = errors.New("unterminated quoted value")
+	errLogfmtSpace = errors.New("expected a space after a quoted value")
+)
+
+// parseLogfmt splits a line into its pairs, in order. A key runs up to an
+// equals sign or a space, and may not contain quotes; an unquoted value runs
+// up to the next space.
+func parseLogfmt(in string) ([]logfmtPair, error) {
+	pairs := make([]logfmtPair, 0)
+	i := 0
+	for {
+		for i < len(in) && in[i] == ' ' {
+			i++
+		}
+		if i == len(in) {
+			return pairs, nil
+		}
+
+		start := i
+		for i < len(in) && in[i] != '=' && in[i] != ' ' {
+			if in[i] == '"' || in[i] < ' ' {
+				return nil, errLogfmtKey
+			}
+			i++
+		}
+		if i == start {
+			return nil, errLogfmtKey
+		}
+		key := in[start:i]
+
+		if i == len(in) || in[i] == ' ' {
+			pairs = append(pairs, logfmtPair{key: key, bare: true})
+			continue
+		}
+
+		// Skip the equals sign.
+		i++
+		if i < len(in) && in[i] == '"' {
+			end, err := quotedEnd(in, i)
+			if err != nil {
+				return nil, err
+			}
+			value, err := strconv.Unquote(in[i:end])
+			if err != nil {
+				return nil, err
+			}
+			if end < len(in) && in[end] != ' ' {
+				return nil, errLogfmtSpace
+			}
+			pairs = append(pairs, logfmtPair{key: key, value: value})
+			i = end
+			continue
+		}
+
+		start = i
+		for i < len(in) && in[i] != ' ' {
+			if in[i] == '"' {
+				return nil, errLogfmtQuote
+			}
+			i++
+		}
+		pairs = append(pairs, logfmtPair{key: key, value: in[start:i]})
+	}
+}
+
+// setPairs stores pairs in a KV map, with bare keys set to true.
+func setPairs(kv map[string]interface{}, pairs []logfmtPair) {
+	for _, p := range pairs {
+		if p.bare {
+			kv[p.key] = true
+		} else {
+			kv[p.key] = p.value
+		}
+	}
+}
+
+// quotedEnd returns the position just past the closing quote of a quoted
+// string starting at i, skipping escaped characters.
+func quotedEnd(in string, i int) (int, error) {
+	for j := i + 1; j < len(in); j++ {
+		switch in[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		}
+	}
+	return 0, errLogfmtQuote
+}
@@ -0,0 +1,139 @@
+package structstream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLogfmtTransformer(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		ok   bool
+
+		message  string
+		severity Severity
+		kv       map[string]interface{}
+	}{
+		{
+			name:     "typical line",
+			in:       `level=info ts=2020-10-17T14:32:05Z msg="listening on :8080" port=8080`,
+			ok:       true,
+			message:  "listening on :8080",
+			severity: SeverityInfo,
+			kv:       map[string]interface{}{"port": "8080"},
+		},
+		{
+			name:    "bare keys are stored as true",
+			in:      `msg=started debug verbose=yes`,
+			ok:      true,
+			message: "started",
+			kv:      map[string]interface{}{"debug": true, "verbose": "yes"},
+		},
+		{
+			name:    "Go escapes in quoted values",
+			in:      `msg="tab\there \"quoted\" é"`,
+			ok:      true,
+			message: "tab\there \"quoted\" é",
+			kv:      map[string]interface{}{},
+		},
+		{
+			name:     "empty values",
+			in:       `lvl=warn msg= caller=`,
+			ok:       true,
+			severity: SeverityWarning,
+			kv:       map[string]interface{}{"caller": ""},
+		},
+		{
+			name: "unterminated quote",
+			in:   `level=info msg="never closed`,
+		},
+		{
+			name: "quoted value not followed by a space",
+			in:   `level=info msg="done"extra`,
+		},
+		{
+			name: "quote inside an unquoted value",
+			in:   `level=info msg=say"hi"`,
+		},
+		{
+			name: "empty key",
+			in:   `level=info =v`,
+		},
+		{
+			name: "quote in a key",
+			in:   `level=info "key"=v`,
+		},
+		{
+			name: "escape that strconv.Unquote rejects",
+			in:   `level=info msg="bad \q escape"`,
+		},
+		{
+			name: "no level, msg, ts or time key",
+			in:   `port=8080 host=example.com`,
+		},
+		{
+			name: "timestamp that does not parse is not a known key",
+			in:   `ts=yesterday port=8080`,
+		},
+		{
+			name: "prose with one key",
+			in:   `hello world msg=x`,
+		},
+		{
+			name:    "as many bare keys as assigned ones",
+			in:      `retrying now msg=x attempt=2`,
+			ok:      true,
+			message: "x",
+			kv:      map[string]interface{}{"retrying": true, "now": true, "attempt": "2"},
+		},
+		{
+			name: "plain text",
+			in:   `Starting server on port 8080`,
+		},
+		{
+			name: "empty line",
+			in:   ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := LogfmtTransformer("meta", tt.in)
+			if ok != tt.ok {
+				t.Fatalf("LogfmtTransformer(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+
+			if !s.Complete {
+				t.Errorf("line is not complete")
+			}
+			if s.Message != tt.message {
+				t.Errorf("message = %q, want %q", s.Message, tt.message)
+			}
+			if s.Severity != tt.severity {
+				t.Errorf("severity = %v, want %v", s.Severity, tt.severity)
+			}
+			if !reflect.DeepEqual(s.KV, tt.kv) {
+				t.Errorf("kv = %#v, want %#v", s.KV, tt.kv)
+			}
+		})
+	}
+}
+
+func TestLogfmtTransformerTimestamp(t *testing.T) {
+	s, ok := LogfmtTransformer("", `time=2020-10-17T14:32:05.5Z msg=x`)
+	if !ok {
+		t.Fatal("line was rejected")
+	}
+	want := time.Date(2020, 10, 17, 14, 32, 5, 5e8, time.UTC)
+	if !s.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", s.Timestamp, want)
+	}
+	if _, ok := s.KV["time"]; ok {
+		t.Errorf("time was not moved out of kv")
+	}
+}
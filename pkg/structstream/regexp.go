@@ -0,0 +1,113 @@
+package structstream
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// RegexpSpec defines a transformer by a regular expression with named
+// groups. The groups named for the timestamp, priority and message fill
+// those fields, and any other named groups are stored in KV.
+type RegexpSpec struct {
+	// Name is the type of the lines that match.
+	Name    string
+	Pattern string
+
+	// TimestampGroup, PriorityGroup and MessageGroup name the groups of the
+	// fields, and default to "timestamp", "priority" and "message".
+	TimestampGroup string
+	PriorityGroup  string
+	MessageGroup   string
+
+	// TimestampLayout is the layout of the timestamp, as for time.Parse. If
+	// it is empty, the formats of timestamps in JSON lines are tried.
+	TimestampLayout string
+}
+
+// NewRegexpTransformer compiles a spec into a transformer, which accepts the
+// lines that the pattern matches. It is an error for the spec to name groups
+// that are not in the pattern, or for the pattern to have no message group.
+func NewRegexpTransformer(spec RegexpSpec) (Transformer, error) {
+	if spec.Name == "" {
+		return nil, errors.New("transformer has no name")
+	}
+	re, err := regexp.Compile(spec.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("transformer %q: %w", spec.Name, err)
+	}
+
+	groups := make(map[string]bool)
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			groups[name] = true
+		}
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("transformer %q: pattern has no named groups", spec.Name)
+	}
+
+	fields := []struct {
+		group *string
+		def   string
+	}{
+		{&spec.TimestampGroup, "timestamp"},
+		{&spec.PriorityGroup, "priority"},
+		{&spec.MessageGroup, "message"},
+	}
+	for _, f := range fields {
+		if *f.group == "" {
+			*f.group = f.def
+			continue
+		}
+		if !groups[*f.group] {
+			return nil, fmt.Errorf("transformer %q: pattern has no group named %q", spec.Name, *f.group)
+		}
+	}
+	if !groups[spec.MessageGroup] {
+		return nil, fmt.Errorf("transformer %q: pattern has no group named %q", spec.Name, spec.MessageGroup)
+	}
+
+	return func(meta, in string) (Structline, bool) {
+		s := Structline{
+			Type: spec.Name,
+			Meta: meta,
+			KV:   make(map[string]interface{}),
+		}
+
+		m := re.FindStringSubmatch(in)
+		if m == nil {
+			return s, false
+		}
+
+		s.Complete = true
+		for i, name := range re.SubexpNames() {
+			switch name {
+			case "":
+			case spec.TimestampGroup:
+				t, ok := parseSpecTime(spec.TimestampLayout, m[i])
+				if ok {
+					s.Timestamp = t
+				} else {
+					s.Complete = false
+				}
+			case spec.PriorityGroup:
+				s.Severity = ParseSeverity(m[i])
+			case spec.MessageGroup:
+				s.Message = m[i]
+			default:
+				s.KV[name] = m[i]
+			}
+		}
+		return s, true
+	}, nil
+}
+
+func parseSpecTime(layout, vs string) (time.Time, bool) {
+	if layout == "" {
+		return parseTime(vs)
+	}
+	t, err := time.Parse(layout, vs)
+	return t, err == nil
+}
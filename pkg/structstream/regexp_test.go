@@ -0,0 +1,192 @@
+package structstream
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRegexpTransformerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec RegexpSpec
+		want string
+	}{
+		{
+			name: "no name",
+			spec: RegexpSpec{Pattern: `(?P<message>.*)`},
+			want: "transformer has no name",
+		},
+		{
+			name: "invalid pattern",
+			spec: RegexpSpec{Name: "billing", Pattern: `(?P<message>.*`},
+			want: `transformer "billing": error parsing regexp`,
+		},
+		{
+			name: "no named groups",
+			spec: RegexpSpec{Name: "billing", Pattern: `(.*)`},
+			want: `transformer "billing": pattern has no named groups`,
+		},
+		{
+			name: "no message group",
+			spec: RegexpSpec{Name: "billing", Pattern: `^(?P<timestamp>\S+) (?P<text>.*)$`},
+			want: `transformer "billing": pattern has no group named "message"`,
+		},
+		{
+			name: "renamed message group not in the pattern",
+			spec: RegexpSpec{Name: "billing", Pattern: `(?P<message>.*)`, MessageGroup: "text"},
+			want: `transformer "billing": pattern has no group named "text"`,
+		},
+		{
+			name: "renamed timestamp group not in the pattern",
+			spec: RegexpSpec{Name: "billing", Pattern: `(?P<message>.*)`, TimestampGroup: "when"},
+			want: `transformer "billing": pattern has no group named "when"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRegexpTransformer(tt.spec)
+			if err == nil {
+				t.Fatal("spec was accepted")
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("error = %q, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexpTransformer(t *testing.T) {
+	spec := RegexpSpec{
+		Name:    "billing",
+		Pattern: `^(?P<timestamp>\S+) (?P<priority>[A-Za-z]+) \[(?P<txn>\w+)\] (?P<message>.*)$`,
+	}
+	transform, err := NewRegexpTransformer(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		ok   bool
+
+		complete  bool
+		timestamp time.Time
+		message   string
+		severity  Severity
+		kv        map[string]interface{}
+	}{
+		{
+			name:      "typical line",
+			in:        `2020-10-17T14:32:05Z INFO [tx1] charged card`,
+			ok:        true,
+			complete:  true,
+			timestamp: time.Date(2020, 10, 17, 14, 32, 5, 0, time.UTC),
+			message:   "charged card",
+			severity:  SeverityInfo,
+			kv:        map[string]interface{}{"txn": "tx1"},
+		},
+		{
+			name:      "severity aliases",
+			in:        `2020-10-17T14:32:05Z warn [tx2] retrying`,
+			ok:        true,
+			complete:  true,
+			timestamp: time.Date(2020, 10, 17, 14, 32, 5, 0, time.UTC),
+			message:   "retrying",
+			severity:  SeverityWarning,
+			kv:        map[string]interface{}{"txn": "tx2"},
+		},
+		{
+			name:      "unknown severity",
+			in:        `2020-10-17T14:32:05Z CHATTY [tx3] hello`,
+			ok:        true,
+			complete:  true,
+			timestamp: time.Date(2020, 10, 17, 14, 32, 5, 0, time.UTC),
+			message:   "hello",
+			kv:        map[string]interface{}{"txn": "tx3"},
+		},
+		{
+			name:     "timestamp that does not parse",
+			in:       `yesterday ERROR [tx4] declined`,
+			ok:       true,
+			message:  "declined",
+			severity: SeverityError,
+			kv:       map[string]interface{}{"txn": "tx4"},
+		},
+		{
+			name: "no match",
+			in:   `charged card`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := transform("meta", tt.in)
+			if ok != tt.ok {
+				t.Fatalf("transform(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+
+			if s.Type != "billing" {
+				t.Errorf("type = %q, want %q", s.Type, "billing")
+			}
+			if s.Complete != tt.complete {
+				t.Errorf("complete = %v, want %v", s.Complete, tt.complete)
+			}
+			if !s.Timestamp.Equal(tt.timestamp) {
+				t.Errorf("timestamp = %v, want %v", s.Timestamp, tt.timestamp)
+			}
+			if s.Message != tt.message {
+				t.Errorf("message = %q, want %q", s.Message, tt.message)
+			}
+			if s.Severity != tt.severity {
+				t.Errorf("severity = %v, want %v", s.Severity, tt.severity)
+			}
+			if !reflect.DeepEqual(s.KV, tt.kv) {
+				t.Errorf("kv = %#v, want %#v", s.KV, tt.kv)
+			}
+		})
+	}
+}
+
+func TestRegexpTransformerLayout(t *testing.T) {
+	transform, err := NewRegexpTransformer(RegexpSpec{
+		Name:            "legacy",
+		Pattern:         `^\[(?P<when>[^\]]+)\] (?P<text>.*)$`,
+		TimestampGroup:  "when",
+		MessageGroup:    "text",
+		TimestampLayout: "02/Jan/2006 15:04:05",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, ok := transform("", `[17/Oct/2020 14:32:05] started`)
+	if !ok {
+		t.Fatal("line was rejected")
+	}
+	if !s.Complete {
+		t.Errorf("line is not complete")
+	}
+	want := time.Date(2020, 10, 17, 14, 32, 5, 0, time.UTC)
+	if !s.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", s.Timestamp, want)
+	}
+	if s.Message != "started" {
+		t.Errorf("message = %q, want %q", s.Message, "started")
+	}
+
+	// The layout replaces the default formats rather than adding to them.
+	s, ok = transform("", `[2020-10-17T14:32:05Z] started`)
+	if !ok {
+		t.Fatal("line was rejected")
+	}
+	if s.Complete || !s.Timestamp.IsZero() {
+		t.Errorf("complete = %v, timestamp = %v, want an incomplete line without a timestamp", s.Complete, s.Timestamp)
+	}
+}
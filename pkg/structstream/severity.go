@@ -0,0 +1,129 @@
+package structstream
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Severity is the severity of a log line. Severities are ordered, so that
+// more severe lines compare greater; the zero value is an unknown severity,
+// which is less than every other.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityTrace
+	SeverityDebug
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityFatal
+)
+
+// Severities are the known severities, from least to most severe.
+var Severities = []Severity{
+	SeverityTrace,
+	SeverityDebug,
+	SeverityInfo,
+	SeverityWarning,
+	SeverityError,
+	SeverityFatal,
+}
+
+var severityNames = map[Severity]string{
+	SeverityTrace:   "TRACE",
+	SeverityDebug:   "DEBUG",
+	SeverityInfo:    "INFO",
+	SeverityWarning: "WARNING",
+	SeverityError:   "ERROR",
+	SeverityFatal:   "FATAL",
+}
+
+// severitySpellings map the names of severities used by various loggers, in
+// upper case, to severities. Levels of Java, syslog and Windows are included.
+var severitySpellings = map[string]Severity{
+	"TRACE":         SeverityTrace,
+	"FINEST":        SeverityTrace,
+	"FINER":         SeverityTrace,
+	"DEBUG":         SeverityDebug,
+	"DBG":           SeverityDebug,
+	"FINE":          SeverityDebug,
+	"CONFIG":        SeverityDebug,
+	"VERBOSE":       SeverityDebug,
+	"INFO":          SeverityInfo,
+	"INF":           SeverityInfo,
+	"INFORMATION":   SeverityInfo,
+	"INFORMATIONAL": SeverityInfo,
+	"NOTICE":        SeverityInfo,
+	"WARN":          SeverityWarning,
+	"WARNING":       SeverityWarning,
+	"WRN":           SeverityWarning,
+	"ERROR":         SeverityError,
+	"ERR":           SeverityError,
+	"SEVERE":        SeverityError,
+	"FATAL":         SeverityFatal,
+	"FTL":           SeverityFatal,
+	"CRIT":          SeverityFatal,
+	"CRITICAL":      SeverityFatal,
+	"ALERT":         SeverityFatal,
+	"EMERG":         SeverityFatal,
+	"EMERGENCY":     SeverityFatal,
+	"PANIC":         SeverityFatal,
+	"DPANIC":        SeverityFatal,
+}
+
+// syslogSeverities map the numeric severities of syslog, from 0 (emergency)
+// to 7 (debug), to severities.
+var syslogSeverities = []Severity{
+	SeverityFatal,
+	SeverityFatal,
+	SeverityFatal,
+	SeverityError,
+	SeverityWarning,
+	SeverityInfo,
+	SeverityInfo,
+	SeverityDebug,
+}
+
+// String returns the canonical name of the severity, in upper case, or the
+// empty string if it is unknown.
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity returns the severity of a name or number, in any of the
+// common spellings and numeric schemes. Numbers from 0 to 7 are syslog
+// severities, and numbers from 10 are the levels of bunyan and pino, from 10
+// (trace) to 60 (fatal). Anything else is unknown.
+func ParseSeverity(v string) Severity {
+	v = strings.TrimSpace(v)
+	if s, ok := severitySpellings[strings.ToUpper(v)]; ok {
+		return s
+	}
+
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return SeverityUnknown
+	}
+	return numericSeverity(n)
+}
+
+func numericSeverity(n float64) Severity {
+	switch {
+	case n >= 0 && n < float64(len(syslogSeverities)) && n == float64(int(n)):
+		return syslogSeverities[int(n)]
+	case n >= 60:
+		return SeverityFatal
+	case n >= 50:
+		return SeverityError
+	case n >= 40:
+		return SeverityWarning
+	case n >= 30:
+		return SeverityInfo
+	case n >= 20:
+		return SeverityDebug
+	case n >= 10:
+		return SeverityTrace
+	}
+	return SeverityUnknown
+}
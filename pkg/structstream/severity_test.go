@@ -0,0 +1,85 @@
+package structstream
+
+import "testing"
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Severity
+	}{
+		// Names, in any case.
+		{"trace", SeverityTrace},
+		{"DEBUG", SeverityDebug},
+		{"Info", SeverityInfo},
+		{"warning", SeverityWarning},
+		{"ERROR", SeverityError},
+		{"fatal", SeverityFatal},
+		{" info ", SeverityInfo},
+
+		// Aliases.
+		{"finest", SeverityTrace},
+		{"dbg", SeverityDebug},
+		{"notice", SeverityInfo},
+		{"informational", SeverityInfo},
+		{"WARN", SeverityWarning},
+		{"err", SeverityError},
+		{"severe", SeverityError},
+		{"crit", SeverityFatal},
+		{"emerg", SeverityFatal},
+		{"dpanic", SeverityFatal},
+		{"panic", SeverityFatal},
+
+		// Syslog, from 0 (emergency) to 7 (debug).
+		{"0", SeverityFatal},
+		{"2", SeverityFatal},
+		{"3", SeverityError},
+		{"4", SeverityWarning},
+		{"5", SeverityInfo},
+		{"6", SeverityInfo},
+		{"7", SeverityDebug},
+
+		// Bunyan and pino, from 10 (trace) to 60 (fatal).
+		{"10", SeverityTrace},
+		{"19", SeverityTrace},
+		{"20", SeverityDebug},
+		{"30", SeverityInfo},
+		{"39.5", SeverityInfo},
+		{"40", SeverityWarning},
+		{"50", SeverityError},
+		{"60", SeverityFatal},
+		{"100", SeverityFatal},
+
+		// Between the schemes, and not severities at all.
+		{"8", SeverityUnknown},
+		{"9.9", SeverityUnknown},
+		{"6.5", SeverityUnknown},
+		{"-1", SeverityUnknown},
+		{"", SeverityUnknown},
+		{"verbose-ish", SeverityUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ParseSeverity(tt.in); got != tt.want {
+			t.Errorf("ParseSeverity(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityOrder(t *testing.T) {
+	prev := SeverityUnknown
+	for _, s := range Severities {
+		if s <= prev {
+			t.Errorf("%v is not more severe than %v", s, prev)
+		}
+		if s.String() == "" {
+			t.Errorf("severity %d has no name", int(s))
+		}
+		if got := ParseSeverity(s.String()); got != s {
+			t.Errorf("ParseSeverity(%q) = %v, want %v", s.String(), got, s)
+		}
+		prev = s
+	}
+	if SeverityUnknown.String() != "" {
+		t.Errorf("unknown severity is named %q", SeverityUnknown.String())
+	}
+}
@@ -12,18 +12,26 @@ type Buffer struct {
 	mu     sync.RWMutex
 	parser Transformer
 
-	metas  []string
-	raw    []string
-	parsed *ristretto.Cache
+	metas []string
+	raw   []string
+	// times holds the times that lines were logged, if known apart from
+	// the lines themselves, or else the zero time; received holds the times
+	// that lines were appended, for lines that do not say when they were
+	// logged.
+	times    []time.Time
+	received []time.Time
+	parsed   *ristretto.Cache
 }
 
 type Structline struct {
 	Complete bool
 	Type     string
 
-	Message   string
-	Meta      string
-	Priority  string
+	Message  string
+	Meta     string
+	Severity Severity
+	// Timestamp is when the line was logged, according to the line, or zero
+	// if it does not say. Lines read from a Buffer are never zero.
 	Timestamp time.Time
 
 	KV map[string]interface{}
@@ -45,17 +53,30 @@ func New(cap int, tr Transformer) (*Buffer, error) {
 		mu:     sync.RWMutex{},
 		parser: tr,
 
-		metas:  make([]string, 0, cap),
-		raw:    make([]string, 0, cap),
-		parsed: cache,
+		metas:    make([]string, 0, cap),
+		raw:      make([]string, 0, cap),
+		times:    make([]time.Time, 0, cap),
+		received: make([]time.Time, 0, cap),
+		parsed:   cache,
 	}, nil
 }
 
-func (b *Buffer) Append(meta, line string) {
+// Append adds a line to the buffer, and returns its position.
+func (b *Buffer) Append(meta, line string) int {
+	return b.AppendAt(meta, line, time.Time{})
+}
+
+// AppendAt adds a line to the buffer with the time it was logged, e.g.,
+// according to the API server, which takes precedence over the timestamp
+// parsed from the line, and returns its position. The zero time is ignored.
+func (b *Buffer) AppendAt(meta, line string, t time.Time) int {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	b.raw = append(b.raw, line)
 	b.metas = append(b.metas, meta)
+	b.times = append(b.times, t)
+	b.received = append(b.received, time.Now())
+	return len(b.raw) - 1
 }
 
 func (b *Buffer) Clear() {
@@ -63,6 +84,18 @@ func (b *Buffer) Clear() {
 	b.mu.Unlock()
 	b.raw = b.raw[:0]
 	b.metas = b.metas[:0]
+	b.times = b.times[:0]
+	b.received = b.received[:0]
+	b.parsed.Clear()
+}
+
+// Reparse discards the parsed lines, so that they are parsed again when they
+// are next read, e.g., after the format of their source changes. Lines being
+// parsed in the meantime are waited for, so that none parsed before the
+// change is cached after it.
+func (b *Buffer) Reparse() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.parsed.Clear()
 }
 
@@ -97,6 +130,11 @@ func (b *Buffer) GetRange(st, fi int) []Structline {
 		if !ok {
 			continue
 		}
+		if !b.times[i].IsZero() {
+			s.Timestamp = b.times[i]
+		} else if s.Timestamp.IsZero() {
+			s.Timestamp = b.received[i]
+		}
 
 		_ = b.parsed.Set(i, s, int64(len(b.raw[i])))
 		ss = append(ss, s)
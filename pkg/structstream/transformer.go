@@ -8,6 +8,9 @@ import (
 
 type Transformer func(string, string) (Structline, bool)
 
+// timeFormats are the formats of timestamps in structured lines.
+var timeFormats = []string{time.RFC822Z, time.RFC1123Z, time.RFC3339, time.RFC3339Nano}
+
 func CombineTransformers(strict bool, ts ...Transformer) Transformer {
 	return func(meta, in string) (Structline, bool) {
 		for _, t := range ts {
@@ -24,26 +27,25 @@ func CombineTransformers(strict bool, ts ...Transformer) Transformer {
 
 func GlogTransformer(meta, in string) (Structline, bool) {
 	s := Structline{
-		Type:      "glog",
-		Meta:      meta,
-		Timestamp: time.Now(),
-		KV:        make(map[string]interface{}),
+		Type: "glog",
+		Meta: meta,
+		KV:   make(map[string]interface{}),
 	}
 	if len(in) < 22 {
 		return s, false
 	}
 
-	v, ok := map[byte]string{
-		'I': "INFO",
-		'W': "WARNING",
-		'E': "ERROR",
-		'F': "FATAL",
+	v, ok := map[byte]Severity{
+		'I': SeverityInfo,
+		'W': SeverityWarning,
+		'E': SeverityError,
+		'F': SeverityFatal,
 	}[in[0]]
 	if !ok {
 		return s, false
 	}
 
-	s.Priority = v
+	s.Severity = v
 
 	segs := strings.SplitN(in, "] ", 2)
 	if len(segs) != 2 {
@@ -83,10 +85,9 @@ func GlogTransformer(meta, in string) (Structline, bool) {
 
 func JSONTransformer(meta, in string) (Structline, bool) {
 	s := Structline{
-		Type:      "json",
-		Meta:      meta,
-		Timestamp: time.Now(),
-		KV:        make(map[string]interface{}),
+		Type: "json",
+		Meta: meta,
+		KV:   make(map[string]interface{}),
 	}
 	if err := json.Unmarshal([]byte(in), &s.KV); err != nil {
 		return s, false
@@ -95,22 +96,14 @@ func JSONTransformer(meta, in string) (Structline, bool) {
 	s.Complete = true
 
 	tryFields(s.KV, []string{"ts", "timestamp", "@ts", "@timestamp"}, func(vs string) bool {
-		for _, format := range []string{time.RFC822Z, time.RFC1123Z, time.RFC3339, time.RFC3339Nano} {
-			t, err := time.Parse(format, vs)
-			if err != nil {
-				continue
-			}
-
+		t, ok := parseTime(vs)
+		if ok {
 			s.Timestamp = t
-			return true
 		}
-		return false
+		return ok
 	})
 
-	tryFields(s.KV, []string{"priority", "prio", "@priority", "@prio"}, func(vs string) bool {
-		s.Priority = vs
-		return true
-	})
+	trySeverity(&s, []string{"level", "severity", "priority", "prio", "lvl", "@level", "@severity", "@priority", "@prio"})
 
 	tryFields(s.KV, []string{"msg", "message", "mesg"}, func(vs string) bool {
 		s.Message = vs
@@ -125,9 +118,8 @@ func PassthruTransformer(meta, in string) (Structline, bool) {
 		Type:     "passthru",
 		Complete: true,
 
-		Message:   in,
-		Meta:      meta,
-		Timestamp: time.Now(),
+		Message: in,
+		Meta:    meta,
 	}
 	return s, true
 }
@@ -152,3 +144,33 @@ func tryFields(kv map[string]interface{}, fields []string, fn func(string) bool)
 		}
 	}
 }
+
+// trySeverity sets the severity of a line from the first of its fields that
+// holds a known severity, either by name or by number, and removes the field.
+func trySeverity(s *Structline, fields []string) {
+	for _, field := range fields {
+		var sev Severity
+		switch v := s.KV[field].(type) {
+		case string:
+			sev = ParseSeverity(v)
+		case float64:
+			sev = numericSeverity(v)
+		}
+		if sev != SeverityUnknown {
+			s.Severity = sev
+			delete(s.KV, field)
+			return
+		}
+	}
+}
+
+// parseTime parses a timestamp in one of the formats that structured logs
+// commonly use.
+func parseTime(vs string) (time.Time, bool) {
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, vs); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
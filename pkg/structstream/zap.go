@@ -0,0 +1,104 @@
+package structstream
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// zapTimeFormats are the formats of the time encoders of zap, which are
+// tried before epoch seconds.
+var zapTimeFormats = []string{
+	"2006-01-02T15:04:05.000Z0700",
+	time.RFC3339Nano,
+	time.RFC3339,
+}
+
+// zapLevels are the levels written by zap's level encoders.
+var zapLevels = map[string]bool{
+	"DEBUG":  true,
+	"INFO":   true,
+	"WARN":   true,
+	"ERROR":  true,
+	"DPANIC": true,
+	"PANIC":  true,
+	"FATAL":  true,
+}
+
+// ZapTransformer parses lines written by the console encoder of zap, which
+// is also used by logr through zapr. Fields are separated by tabs: the time,
+// level, logger name and caller, if enabled, the message, and any context as
+// a JSON object, e.g.:
+//
+//	2020-10-17T14:32:05.123Z	INFO	setup	main.go:42	starting manager	{"version": "1.2"}
+func ZapTransformer(meta, in string) (Structline, bool) {
+	s := Structline{
+		Type: "zap",
+		Meta: meta,
+		KV:   make(map[string]interface{}),
+	}
+
+	fields := strings.Split(in, "\t")
+	if len(fields) < 3 {
+		return s, false
+	}
+
+	t, ok := parseZapTime(fields[0])
+	if !ok {
+		return s, false
+	}
+	level := strings.ToUpper(fields[1])
+	if !zapLevels[level] {
+		return s, false
+	}
+	s.Timestamp = t
+	s.Severity = ParseSeverity(level)
+
+	rest := fields[2:]
+	if last := rest[len(rest)-1]; len(rest) > 1 && strings.HasPrefix(last, "{") {
+		if err := json.Unmarshal([]byte(last), &s.KV); err != nil {
+			return s, false
+		}
+		rest = rest[:len(rest)-1]
+	}
+
+	// The message is the last remaining field; before it are the logger
+	// name and the caller, either of which may be turned off.
+	s.Message = rest[len(rest)-1]
+	for _, f := range rest[:len(rest)-1] {
+		if isCaller(f) {
+			s.KV["caller"] = f
+		} else {
+			s.KV["logger"] = f
+		}
+	}
+
+	s.Complete = true
+	return s, true
+}
+
+// isCaller returns whether a field looks like the file:line of a caller.
+func isCaller(f string) bool {
+	i := strings.LastIndexByte(f, ':')
+	if i <= 0 {
+		return false
+	}
+	_, err := strconv.Atoi(f[i+1:])
+	return err == nil
+}
+
+func parseZapTime(f string) (time.Time, bool) {
+	for _, format := range zapTimeFormats {
+		if t, err := time.Parse(format, f); err == nil {
+			return t, true
+		}
+	}
+
+	// The epoch encoders write seconds, with a fraction.
+	secs, err := strconv.ParseFloat(f, 64)
+	if err != nil || !strings.Contains(f, ".") {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(secs*float64(time.Second))), true
+}
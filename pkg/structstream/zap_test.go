@@ -0,0 +1,176 @@
+package structstream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestZapTransformer(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		ok   bool
+
+		message  string
+		severity Severity
+		kv       map[string]interface{}
+	}{
+		{
+			name:     "logger, caller and context",
+			in:       "2020-10-17T14:32:05.123Z\tINFO\tsetup\tmain.go:42\tstarting manager\t{\"version\": \"1.2\"}",
+			ok:       true,
+			message:  "starting manager",
+			severity: SeverityInfo,
+			kv: map[string]interface{}{
+				"logger":  "setup",
+				"caller":  "main.go:42",
+				"version": "1.2",
+			},
+		},
+		{
+			name:     "caller without logger",
+			in:       "2020-10-17T14:32:05.123Z\tWARN\tcontrollers/pod.go:7\tslow reconcile",
+			ok:       true,
+			message:  "slow reconcile",
+			severity: SeverityWarning,
+			kv:       map[string]interface{}{"caller": "controllers/pod.go:7"},
+		},
+		{
+			name:     "logger without caller",
+			in:       "2020-10-17T14:32:05.123Z\tERROR\tcontroller.pod\treconcile failed\t{\"attempt\": 3}",
+			ok:       true,
+			message:  "reconcile failed",
+			severity: SeverityError,
+			kv: map[string]interface{}{
+				"logger":  "controller.pod",
+				"attempt": float64(3),
+			},
+		},
+		{
+			name:     "message only",
+			in:       "2020-10-17T14:32:05.123Z\tDEBUG\tpolling",
+			ok:       true,
+			message:  "polling",
+			severity: SeverityDebug,
+			kv:       map[string]interface{}{},
+		},
+		{
+			name:     "lowercase level",
+			in:       "2020-10-17T14:32:05Z\tinfo\tready",
+			ok:       true,
+			message:  "ready",
+			severity: SeverityInfo,
+			kv:       map[string]interface{}{},
+		},
+		{
+			name:     "dpanic",
+			in:       "2020-10-17T14:32:05Z\tDPANIC\tinvariant broken",
+			ok:       true,
+			message:  "invariant broken",
+			severity: SeverityFatal,
+			kv:       map[string]interface{}{},
+		},
+		{
+			name:     "fatal",
+			in:       "2020-10-17T14:32:05Z\tFATAL\tgiving up",
+			ok:       true,
+			message:  "giving up",
+			severity: SeverityFatal,
+			kv:       map[string]interface{}{},
+		},
+		{
+			name: "unknown level",
+			in:   "2020-10-17T14:32:05Z\tNOTICE\thello",
+		},
+		{
+			name: "time that does not parse",
+			in:   "yesterday\tINFO\thello",
+		},
+		{
+			name: "context that is not JSON",
+			in:   "2020-10-17T14:32:05Z\tINFO\thello\t{not json",
+		},
+		{
+			name: "plain text",
+			in:   "Starting server on port 8080",
+		},
+		{
+			name: "plain text with tabs",
+			in:   "name\tvalue\tmore",
+		},
+		{
+			name: "empty line",
+			in:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := ZapTransformer("meta", tt.in)
+			if ok != tt.ok {
+				t.Fatalf("ZapTransformer(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+
+			if !s.Complete {
+				t.Errorf("line is not complete")
+			}
+			if s.Message != tt.message {
+				t.Errorf("message = %q, want %q", s.Message, tt.message)
+			}
+			if s.Severity != tt.severity {
+				t.Errorf("severity = %v, want %v", s.Severity, tt.severity)
+			}
+			if !reflect.DeepEqual(s.KV, tt.kv) {
+				t.Errorf("kv = %#v, want %#v", s.KV, tt.kv)
+			}
+		})
+	}
+}
+
+func TestZapTransformerTimestamp(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{
+			name: "ISO8601 with milliseconds",
+			in:   "2020-10-17T14:32:05.123Z\tINFO\tx",
+			want: time.Date(2020, 10, 17, 14, 32, 5, 123e6, time.UTC),
+		},
+		{
+			name: "ISO8601 with an offset",
+			in:   "2020-10-17T14:32:05.123-0700\tINFO\tx",
+			want: time.Date(2020, 10, 17, 21, 32, 5, 123e6, time.UTC),
+		},
+		{
+			name: "epoch seconds",
+			in:   "1602945125.5\tINFO\tx",
+			want: time.Date(2020, 10, 17, 14, 32, 5, 5e8, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := ZapTransformer("", tt.in)
+			if !ok {
+				t.Fatal("line was rejected")
+			}
+			if d := s.Timestamp.Sub(tt.want); d < -time.Millisecond || d > time.Millisecond {
+				t.Errorf("timestamp = %v, want %v", s.Timestamp, tt.want)
+			}
+		})
+	}
+}
+
+func TestZapTransformerEpochInteger(t *testing.T) {
+	// Epoch seconds are always written with a fraction; a bare integer is
+	// more likely a line of plain text.
+	if _, ok := ZapTransformer("", "42\tINFO\tx"); ok {
+		t.Error("line with an integer time was accepted")
+	}
+}
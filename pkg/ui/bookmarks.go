@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// bookmarkActions wait for a letter, which names the bookmark, as marks do
+// in vim and less.
+var bookmarkActions = []Action{
+	{
+		Name:   "bookmark.set",
+		Keymap: keymapNormal,
+		Help:   "Bookmark the selected line with the next letter typed",
+		Keys:   []string{"m"},
+		Run:    func(u *UI) { u.letterCmd = (*UI).SetBookmark },
+	},
+	{
+		Name:   "bookmark.jump",
+		Keymap: keymapNormal,
+		Help:   "Jump to the bookmark of the next letter typed",
+		Keys:   []string{"'"},
+		Run:    func(u *UI) { u.letterCmd = (*UI).JumpToBookmark },
+	},
+	{
+		Name:   "bookmark.annotate",
+		Keymap: keymapNormal,
+		Help:   "Annotate the bookmark of the next letter typed",
+		Keys:   []string{"A"},
+		Run:    func(u *UI) { u.letterCmd = (*UI).AnnotateBookmark },
+	},
+	{
+		Name:   "bookmark.list",
+		Keymap: keymapGlobal,
+		Help:   "Show or hide the list of bookmarks",
+		Keys:   []string{"M"},
+		Run:    (*UI).ToggleBookmarks,
+	},
+	{
+		Name:   "session.export",
+		Keymap: keymapNormal,
+		Help:   "Export the scrollback and bookmarks to a file",
+		Keys:   []string{"E"},
+		Run: func(u *UI) {
+			u.Prompt("export to ", func(path string) {
+				if path == "" {
+					return
+				}
+				if err := u.Export(path); err != nil {
+					u.SetMessage(fmt.Sprintf("cannot export: %+v", err))
+				}
+			})
+		},
+	},
+}
+
+// AnnotateBookmark prompts for the annotation of a bookmark, starting from
+// its current annotation. An empty annotation removes it.
+func (u *UI) AnnotateBookmark(letter rune) {
+	bm, ok := u.bookmarks.Get(letter)
+	if !ok {
+		u.SetMessage(fmt.Sprintf("no bookmark '%c", letter))
+		return
+	}
+
+	u.Prompt(fmt.Sprintf("annotate '%c: ", letter), func(text string) {
+		u.bookmarks.Annotate(letter, text)
+		u.updateBookmarks()
+	})
+	u.input.SetText(bm.Annotation)
+}
+
+// JumpToBookmark selects the line of a bookmark, unless it is hidden by the
+// filters of the focused pane.
+func (u *UI) JumpToBookmark(letter rune) {
+	bm, ok := u.bookmarks.Get(letter)
+	if !ok {
+		u.SetMessage(fmt.Sprintf("no bookmark '%c", letter))
+		return
+	}
+
+	if !u.unfollow().SetCursor(bm.Index) {
+		u.SetMessage(fmt.Sprintf("bookmark '%c is hidden by filters", letter))
+	}
+}
+
+// SetBookmark bookmarks the selected line, or the last line on screen if no
+// line is selected.
+func (u *UI) SetBookmark(letter rune) {
+	idx, ok := u.pager.Current()
+	if !ok {
+		u.SetMessage("no line to bookmark")
+		return
+	}
+
+	u.bookmarks.Set(letter, idx)
+	u.updateBookmarks()
+	u.SetMessage(fmt.Sprintf("bookmarked '%c", letter))
+}
+
+// ToggleBookmarks shows or hides the panel listing the bookmarks.
+func (u *UI) ToggleBookmarks() {
+	u.showBookmarks = !u.showBookmarks
+	if u.showBookmarks {
+		u.body.AddWidget(u.bookmarkList, 0)
+	} else {
+		u.body.RemoveWidget(u.bookmarkList)
+	}
+}
+
+// exportedLine is a line of scrollback as written by Export, one JSON object
+// per line.
+type exportedLine struct {
+	Source    string             `json:"source"`
+	Text      string             `json:"text"`
+	Priority  string             `json:"priority,omitempty"`
+	Bookmarks []exportedBookmark `json:"bookmarks,omitempty"`
+}
+
+type exportedBookmark struct {
+	Letter     string `json:"letter"`
+	Annotation string `json:"annotation,omitempty"`
+}
+
+// Export writes every line of the scrollback, regardless of filters, to a
+// file as JSON lines. Bookmarked lines carry their bookmarks and annotations.
+func (u *UI) Export(path string) error {
+	marks := make(map[int][]exportedBookmark)
+	for _, bm := range u.bookmarks.All() {
+		marks[bm.Index] = append(marks[bm.Index], exportedBookmark{
+			Letter:     string(bm.Letter),
+			Annotation: bm.Annotation,
+		})
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	// The file is closed on every path, and an error from closing it, which
+	// may lose what was written, is only reported if writing succeeded.
+	err = u.writeExport(f, marks)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	u.SetMessage(fmt.Sprintf("exported %d lines and %d bookmarks to %s", u.scrollback.Len(), len(u.bookmarks.All()), path))
+	return nil
+}
+
+// writeExport writes the scrollback to w as JSON lines, with the bookmarks of
+// each line.
+func (u *UI) writeExport(w io.Writer, marks map[int][]exportedBookmark) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for i := 0; i < u.scrollback.Len(); i++ {
+		line := u.scrollback.At(i)
+		err := enc.Encode(exportedLine{
+			Source:    line.Source,
+			Text:      line.FullText(),
+			Priority:  line.Severity.String(),
+			Bookmarks: marks[i],
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// handleLetter passes the letter typed after a bookmark key to the command
+// waiting for it. Any other key cancels the command.
+func (u *UI) handleLetter(ek *tcell.EventKey) {
+	fn := u.letterCmd
+	u.letterCmd = nil
+
+	r := ek.Rune()
+	if ek.Key() != tcell.KeyRune || !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+		return
+	}
+	fn(u, r)
+}
+
+func (u *UI) updateBookmarks() {
+	u.bookmarkList.SetBookmarks(u.bookmarks.All())
+}
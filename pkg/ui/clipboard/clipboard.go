@@ -0,0 +1,35 @@
+// Package clipboard copies text to the system clipboard of the terminal
+// emulator, using the OSC 52 escape sequence. Because the sequence is
+// interpreted by the terminal, it also works over SSH.
+package clipboard
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+)
+
+// ttyPath is the controlling terminal, which tcell also draws to, so that the
+// sequence reaches the terminal even when stdout is redirected.
+const ttyPath = "/dev/tty"
+
+// Copy writes text to the clipboard of the controlling terminal. It must be
+// called from the same goroutine that draws the screen, e.g., an event
+// handler, so that the sequence is not written in the middle of a draw.
+func Copy(text string) error {
+	tty, err := os.OpenFile(ttyPath, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	err = Write(tty, text)
+	if cerr := tty.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Write writes the OSC 52 sequence that sets the clipboard to text.
+func Write(w io.Writer, text string) error {
+	_, err := io.WriteString(w, "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte(text))+"\a")
+	return err
+}
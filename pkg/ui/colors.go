@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+// Colorizer assigns each log source a colour from the theme palette. The
+// colour is derived from a hash of the source, i.e., the namespace, pod and
+// container name, so that it stays the same across restarts of axe.
+type Colorizer struct {
+	base    tcell.Style
+	palette []tcell.Color
+	seen    map[string]tcell.Style
+}
+
+func NewColorizer(base tcell.Style, palette []tcell.Color) *Colorizer {
+	return &Colorizer{
+		base:    base,
+		palette: palette,
+		seen:    make(map[string]tcell.Style),
+	}
+}
+
+// Legend returns the sources seen so far and their styles, sorted by name.
+func (c *Colorizer) Legend() []widgets.LegendEntry {
+	es := make([]widgets.LegendEntry, 0, len(c.seen))
+	for label, style := range c.seen {
+		es = append(es, widgets.LegendEntry{Label: label, Style: style})
+	}
+
+	sort.Slice(es, func(i, j int) bool {
+		return es[i].Label < es[j].Label
+	})
+	return es
+}
+
+// Style returns the style for a source, and whether this is the first time
+// the source has been seen.
+func (c *Colorizer) Style(key string) (tcell.Style, bool) {
+	if s, ok := c.seen[key]; ok {
+		return s, false
+	}
+
+	s := c.base
+	if len(c.palette) > 0 {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		s = s.Foreground(c.palette[h.Sum32()%uint32(len(c.palette))])
+	}
+
+	c.seen[key] = s
+	return s, true
+}
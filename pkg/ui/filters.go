@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"github.com/ripta/axe/pkg/query"
+	"github.com/ripta/axe/pkg/structstream"
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+// QueryFilter shows lines whose structured form satisfies a query.
+type QueryFilter struct {
+	buf *structstream.Buffer
+	q   *query.Query
+}
+
+func NewQueryFilter(buf *structstream.Buffer, q *query.Query) *QueryFilter {
+	return &QueryFilter{
+		buf: buf,
+		q:   q,
+	}
+}
+
+func (f *QueryFilter) Match(l widgets.Line) bool {
+	return f.q.Match(f.buf.GetAt(l.Index))
+}
+
+func (f *QueryFilter) String() string {
+	return "where:" + f.q.String()
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ripta/axe/pkg/structstream"
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+var formatActions = []Action{
+	{
+		Name:   "format.set",
+		Keymap: keymapNormal,
+		Help:   "Set the log format of the selected line's container image",
+		Keys:   []string{"#"},
+		Run:    (*UI).SetFormat,
+	},
+}
+
+// SetDetector sets the detector of the log format of each container, whose
+// choices can be shown and overridden.
+func (u *UI) SetDetector(d *structstream.Detector) {
+	u.detector = d
+}
+
+// SetFormat prompts for the log format of the selected line's container, and
+// of every container with the same image. An empty format detects it again.
+func (u *UI) SetFormat() {
+	if u.detector == nil {
+		return
+	}
+	idx, ok := u.pager.Current()
+	if !ok {
+		u.SetMessage("no line to set the format of")
+		return
+	}
+	source := u.scrollback.At(idx).Source
+
+	// The prompt shows the current format, and is empty unless it was set
+	// by the user, which an empty format undoes.
+	prompt, current := "format (empty to detect) ", ""
+	if det, ok := u.detector.Detection(source); ok {
+		prompt = fmt.Sprintf("format [%s] ", det)
+		if det.Override {
+			current = det.Format
+		}
+	}
+
+	u.Prompt(prompt, func(text string) {
+		name := strings.TrimSpace(text)
+		if err := u.detector.Override(source, name); err != nil {
+			u.SetMessage(fmt.Sprintf("cannot set format: %+v", err))
+			return
+		}
+
+		u.reparse(source)
+		if name == "" {
+			u.SetMessage(fmt.Sprintf("%s: detecting format", source))
+			return
+		}
+		u.SetMessage(fmt.Sprintf("%s: %s", source, name))
+	})
+	u.input.SetText(current)
+}
+
+// reparse parses the lines of the sources that share a format with a source
+// again, after the format changes, and updates the severities and times that
+// the lines are shown and filtered by.
+func (u *UI) reparse(source string) {
+	u.buffer.Reparse()
+
+	refresh := func(line widgets.Line) (widgets.Line, bool) {
+		if !u.detector.SameKey(line.Source, source) {
+			return line, false
+		}
+		s := u.buffer.GetAt(line.Index)
+		line.Severity = s.Severity
+		line.Time = s.Timestamp
+		line.Style = u.priorities.Select(line.Severity)
+		return line, true
+	}
+
+	for i := 0; i < u.scrollback.Len(); i++ {
+		if line, ok := refresh(u.scrollback.At(i)); ok {
+			u.scrollback.Set(i, line)
+		}
+	}
+	for i, line := range u.held {
+		u.held[i], _ = refresh(line)
+	}
+
+	for _, pn := range u.panes {
+		pn.Pager.Refilter()
+	}
+	u.updateScroll()
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2/views"
+
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+// helpMargin is the space kept between the help overlay and the edges of the
+// screen.
+const helpMargin = 2
+
+// keymapTitles are the headings of the help sections, in the order they are
+// shown.
+var keymapTitles = []struct {
+	keymap string
+	title  string
+}{
+	{keymapGlobal, "Everywhere"},
+	{keymapNormal, "Panes"},
+	{keymapTable, "Table mode"},
+	{keymapSidebar, "Sidebar"},
+	{keymapInspector, "Inspector"},
+	{keymapWindow, "After Ctrl-W"},
+	{keymapHelp, "Help"},
+}
+
+var helpActions = []Action{
+	{
+		Name:   "help.show",
+		Keymap: keymapGlobal,
+		Help:   "Show the keys and what they do",
+		Keys:   []string{"?", "F1"},
+		Run:    (*UI).ShowHelp,
+	},
+	{
+		Name:   "help.close",
+		Keymap: keymapHelp,
+		Help:   "Close the help",
+		Keys:   []string{"Esc", "q", "?", "F1"},
+		Run:    (*UI).CloseHelp,
+	},
+	{
+		Name:   "help.down",
+		Keymap: keymapHelp,
+		Help:   "Scroll down a line",
+		Keys:   []string{"j", "Down"},
+		Run:    func(u *UI) { u.help.ScrollDown(1) },
+	},
+	{
+		Name:   "help.up",
+		Keymap: keymapHelp,
+		Help:   "Scroll up a line",
+		Keys:   []string{"k", "Up"},
+		Run:    func(u *UI) { u.help.ScrollUp(1) },
+	},
+	{
+		Name:   "help.page-down",
+		Keymap: keymapHelp,
+		Help:   "Scroll down a screen",
+		Keys:   []string{"Space", "PgDn", "Ctrl-D"},
+		Run:    func(u *UI) { u.help.ScrollDown(u.helpHeight()) },
+	},
+	{
+		Name:   "help.page-up",
+		Keymap: keymapHelp,
+		Help:   "Scroll up a screen",
+		Keys:   []string{"PgUp", "Ctrl-U"},
+		Run:    func(u *UI) { u.help.ScrollUp(u.helpHeight()) },
+	},
+}
+
+// CloseHelp hides the help overlay.
+func (u *UI) CloseHelp() {
+	u.showHelp = false
+}
+
+// Draw draws the UI, and then the help overlay over it, if it is shown.
+func (u *UI) Draw() {
+	u.BoxLayout.Draw()
+	if u.showHelp {
+		u.help.Draw()
+	}
+}
+
+func (u *UI) Resize() {
+	u.BoxLayout.Resize()
+	u.layoutHelp()
+}
+
+// ShowHelp shows an overlay listing every action and the keys bound to it,
+// grouped by where the keys apply.
+func (u *UI) ShowHelp() {
+	byKeymap := make(map[string][]widgets.HelpRow)
+	for _, a := range u.bindings.order {
+		byKeymap[a.Keymap] = append(byKeymap[a.Keymap], widgets.HelpRow{
+			Keys: strings.Join(u.bindings.Keys(a.Name), " "),
+			Help: a.Help,
+			Name: a.Name,
+		})
+	}
+
+	sections := make([]widgets.HelpSection, 0, len(keymapTitles))
+	for _, kt := range keymapTitles {
+		if rows := byKeymap[kt.keymap]; len(rows) > 0 {
+			sections = append(sections, widgets.HelpSection{Title: kt.title, Rows: rows})
+		}
+	}
+
+	u.help.SetSections(sections)
+	u.showHelp = true
+	u.layoutHelp()
+}
+
+// helpHeight returns the number of rows of help shown at a time.
+func (u *UI) helpHeight() int {
+	_, h := u.help.Size()
+	if u.view != nil {
+		if _, vh := u.view.Size(); vh-2*helpMargin < h {
+			h = vh - 2*helpMargin
+		}
+	}
+	return h - 2
+}
+
+// layoutHelp centers the help overlay on the screen, as large as its
+// contents, within a margin.
+func (u *UI) layoutHelp() {
+	if u.view == nil || !u.showHelp {
+		return
+	}
+
+	sw, sh := u.view.Size()
+	w, h := u.help.Size()
+	if max := sw - 2*helpMargin; w > max {
+		w = max
+	}
+	if max := sh - 2*helpMargin; h > max {
+		h = max
+	}
+	u.help.SetView(views.NewViewPort(u.view, (sw-w)/2, (sh-h)/2, w, h))
+}
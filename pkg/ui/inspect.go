@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ripta/axe/pkg/kubelogs"
+	"github.com/ripta/axe/pkg/structstream"
+	"github.com/ripta/axe/pkg/ui/clipboard"
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+// PodInfoFunc looks up a summary of a pod by namespace and name.
+type PodInfoFunc func(ns, name string) (kubelogs.PodInfo, bool)
+
+var inspectorActions = []Action{
+	{
+		Name:   "inspector.close",
+		Keymap: keymapInspector,
+		Help:   "Close the inspector",
+		Keys:   []string{"Esc", "q"},
+		Run:    (*UI).CloseInspector,
+	},
+	{
+		Name:   "inspector.next",
+		Keymap: keymapInspector,
+		Help:   "Select the next field",
+		Keys:   []string{"j", "Down"},
+		Run:    func(u *UI) { u.inspector.SelectNext() },
+	},
+	{
+		Name:   "inspector.prev",
+		Keymap: keymapInspector,
+		Help:   "Select the previous field",
+		Keys:   []string{"k", "Up"},
+		Run:    func(u *UI) { u.inspector.SelectPrev() },
+	},
+	{
+		Name:   "inspector.toggle",
+		Keymap: keymapInspector,
+		Help:   "Collapse or expand the selected field",
+		Keys:   []string{"Enter", "Left", "Right", "Space"},
+		Run:    func(u *UI) { u.inspector.Toggle() },
+	},
+	{
+		Name:   "inspector.copy",
+		Keymap: keymapInspector,
+		Help:   "Copy the value of the selected field to the clipboard",
+		Keys:   []string{"y"},
+		Run:    (*UI).CopySelectedField,
+	},
+}
+
+// CloseInspector hides the inspector pane.
+func (u *UI) CloseInspector() {
+	if !u.showInspector {
+		return
+	}
+	u.showInspector = false
+	u.body.RemoveWidget(u.inspector)
+}
+
+// CopySelectedField copies the value of the selected inspector field to the
+// terminal's clipboard.
+func (u *UI) CopySelectedField() {
+	n, ok := u.inspector.Selected()
+	if !ok {
+		return
+	}
+
+	if err := clipboard.Copy(n.Value); err != nil {
+		u.SetMessage(fmt.Sprintf("could not copy %s: %+v", n.Key, err))
+		return
+	}
+	u.SetMessage(fmt.Sprintf("copied %s to clipboard", n.Key))
+}
+
+// Inspect opens the inspector on the selected line, or the last line if no
+// line is selected.
+func (u *UI) Inspect() {
+	line, ok := u.pager.Cursor()
+	if !ok {
+		u.pager.MoveCursor(0)
+		if line, ok = u.pager.Cursor(); !ok {
+			return
+		}
+	}
+
+	s := u.buffer.GetAt(line.Index)
+	u.inspector.SetNodes(line.Source, u.inspectNodes(line, s))
+	if !u.showInspector {
+		u.showInspector = true
+		u.body.AddWidget(u.inspector, 1)
+	}
+}
+
+// SetPodInfo sets the function used to look up pod metadata in the
+// inspector.
+func (u *UI) SetPodInfo(fn PodInfoFunc) {
+	u.podInfo = fn
+}
+
+func (u *UI) inspectNodes(line widgets.Line, s structstream.Structline) []*widgets.InspectorNode {
+	nodes := []*widgets.InspectorNode{
+		{Key: "line", Value: line.Text},
+		{Key: "message", Value: s.Message},
+		{Key: "severity", Value: s.Severity.String()},
+		{
+			Key:   "timestamp",
+			Value: s.Timestamp.Format(time.RFC3339Nano),
+			Children: []*widgets.InspectorNode{
+				{Key: "local", Value: s.Timestamp.Local().Format(time.RFC3339Nano)},
+				{Key: "utc", Value: s.Timestamp.UTC().Format(time.RFC3339Nano)},
+			},
+		},
+		{Key: "type", Value: s.Type},
+		valueNode("kv", s.KV),
+	}
+	if u.detector != nil {
+		if det, ok := u.detector.Detection(line.Source); ok {
+			nodes = append(nodes, &widgets.InspectorNode{Key: "format", Value: det.String()})
+		}
+	}
+
+	segs := strings.SplitN(line.Source, "/", 3)
+	if len(segs) != 3 {
+		return nodes
+	}
+
+	nodes = append(nodes, &widgets.InspectorNode{
+		Key:   "source",
+		Value: line.Source,
+		Children: []*widgets.InspectorNode{
+			{Key: "namespace", Value: segs[0]},
+			{Key: "pod", Value: segs[1]},
+			{Key: "container", Value: segs[2]},
+		},
+	})
+
+	if u.podInfo == nil {
+		return nodes
+	}
+	pi, ok := u.podInfo(segs[0], segs[1])
+	if !ok {
+		return nodes
+	}
+
+	pn := &widgets.InspectorNode{
+		Key:   "pod",
+		Value: segs[1],
+		Children: []*widgets.InspectorNode{
+			{Key: "node", Value: pi.Node},
+			{Key: "phase", Value: pi.Phase},
+			{Key: "ip", Value: pi.IP},
+			{Key: "started", Value: pi.StartTime.Local().Format(time.RFC3339)},
+		},
+	}
+
+	labels := make(map[string]interface{}, len(pi.Labels))
+	for k, v := range pi.Labels {
+		labels[k] = v
+	}
+	ln := valueNode("labels", labels)
+	ln.Collapsed = true
+	pn.Children = append(pn.Children, ln)
+
+	cn := &widgets.InspectorNode{Key: "containers"}
+	for _, ci := range pi.Containers {
+		cn.Children = append(cn.Children, &widgets.InspectorNode{
+			Key:   ci.Name,
+			Value: ci.Image,
+			Children: []*widgets.InspectorNode{
+				{Key: "image", Value: ci.Image},
+				{Key: "ready", Value: strconv.FormatBool(ci.Ready)},
+				{Key: "restarts", Value: strconv.Itoa(int(ci.Restarts))},
+			},
+		})
+	}
+	pn.Children = append(pn.Children, cn)
+
+	return append(nodes, pn)
+}
+
+// valueNode converts a decoded JSON value into a tree of nodes. Maps and
+// slices become nodes with children, whose value is their JSON encoding.
+func valueNode(key string, v interface{}) *widgets.InspectorNode {
+	n := &widgets.InspectorNode{Key: key}
+
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(tv))
+		for k := range tv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			n.Children = append(n.Children, valueNode(k, tv[k]))
+		}
+		n.Value = jsonString(tv)
+	case []interface{}:
+		for i, e := range tv {
+			n.Children = append(n.Children, valueNode(fmt.Sprintf("[%d]", i), e))
+		}
+		n.Value = jsonString(tv)
+	case string:
+		n.Value = tv
+	case nil:
+		n.Value = "null"
+	default:
+		n.Value = fmt.Sprint(tv)
+	}
+	return n
+}
+
+func jsonString(v interface{}) string {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bs)
+}
@@ -0,0 +1,268 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Keymaps group the actions that are available at the same time. Keys are
+// looked up in the keymaps that apply to what has focus, in order, so a
+// binding in one keymap hides the same key in the keymaps after it.
+const (
+	keymapGlobal    = "global"
+	keymapNormal    = "normal"
+	keymapTable     = "table"
+	keymapSidebar   = "sidebar"
+	keymapInspector = "inspector"
+	keymapWindow    = "window"
+	keymapHelp      = "help"
+)
+
+// Action is something that the user can do by pressing a key.
+type Action struct {
+	// Name identifies the action in key bindings, e.g., "scroll.page-down".
+	Name   string
+	Keymap string
+	Help   string
+	// Keys are the keys bound to the action by default.
+	Keys []string
+	Run  func(u *UI)
+}
+
+// actions is the registry of every action, in the order they are listed in
+// help.
+var actions = concatActions(
+	appActions,
+	filterActions,
+	scrollActions,
+	bookmarkActions,
+	timeActions,
+	formatActions,
+	tableActions,
+	sidebarActions,
+	inspectorActions,
+	paneActions,
+	helpActions,
+)
+
+// overlaid are the keymaps that are looked up together with each keymap, in
+// which a key bound in one would silently hide the same key in another. The
+// table keymap is looked up before the normal one, whose keys still apply in
+// table mode. Other keymaps are modes, which hide the keys of the global
+// keymap on purpose.
+var overlaid = map[string][]string{
+	keymapGlobal: {keymapNormal, keymapTable},
+	keymapNormal: {keymapGlobal, keymapTable},
+	keymapTable:  {keymapGlobal, keymapNormal},
+}
+
+// presets replace the default keys of some actions, to suit the habits of
+// users of other programs.
+var presets = map[string]map[string][]string{
+	"default": {},
+	"vim": {
+		"cursor.down":           {"j", "Down"},
+		"cursor.up":             {"k", "Up"},
+		"scroll.half-page-down": {"Ctrl-D"},
+		"scroll.half-page-up":   {"Ctrl-U"},
+		"scroll.page-down":      {"Ctrl-F", "PgDn"},
+		"scroll.page-up":        {"Ctrl-B", "PgUp"},
+		"scroll.left":           {"h", "Left"},
+		"scroll.right":          {"l", "Right"},
+		"scroll.home":           {"0", "Home"},
+	},
+	"less": {
+		"follow.toggle":         {"F"},
+		"scroll.line-down":      {"j", "e", "Ctrl-E", "Ctrl-N"},
+		"scroll.line-up":        {"k", "y", "Ctrl-Y", "Ctrl-P"},
+		"scroll.half-page-down": {"d", "Ctrl-D"},
+		"scroll.half-page-up":   {"u", "Ctrl-U"},
+		"scroll.page-down":      {"f", "Space", "Ctrl-F", "PgDn"},
+		"scroll.page-up":        {"b", "Ctrl-B", "PgUp"},
+		"scroll.top":            {"g", "Home"},
+		"scroll.bottom":         {"G", "End"},
+		"scroll.home":           {},
+	},
+}
+
+// keyNames maps the lowercased names of special keys to their names as
+// written by tcell, e.g., "ctrl-c" to "Ctrl-C".
+var keyNames = func() map[string]string {
+	names := map[string]string{"space": "Space"}
+	for _, name := range tcell.KeyNames {
+		names[strings.ToLower(name)] = name
+	}
+	return names
+}()
+
+// Bindings maps keys to actions in each keymap.
+type Bindings struct {
+	keys    map[string]map[string]*Action
+	actions map[string][]string
+	order   []*Action
+}
+
+// NewBindings binds the keys of a preset, with the keys of some actions
+// replaced. Unknown presets, actions, or keys are an error, as is binding a
+// key to more than one action in the same keymap, or in overlaid keymaps.
+func NewBindings(preset string, overrides map[string][]string) (*Bindings, error) {
+	if preset == "" {
+		preset = "default"
+	}
+	pkeys, ok := presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown key binding preset %q", preset)
+	}
+
+	byName := make(map[string]*Action, len(actions))
+	for i := range actions {
+		byName[actions[i].Name] = &actions[i]
+	}
+	for name := range overrides {
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("cannot bind keys to unknown action %q", name)
+		}
+	}
+
+	b := &Bindings{
+		keys:    make(map[string]map[string]*Action),
+		actions: make(map[string][]string),
+	}
+
+	conflicts := make([]string, 0)
+	for i := range actions {
+		a := &actions[i]
+		b.order = append(b.order, a)
+
+		keys, ok := overrides[a.Name]
+		if !ok {
+			if keys, ok = pkeys[a.Name]; !ok {
+				keys = a.Keys
+			}
+		}
+
+		if b.keys[a.Keymap] == nil {
+			b.keys[a.Keymap] = make(map[string]*Action)
+		}
+		for _, k := range keys {
+			key, err := parseKey(k)
+			if err != nil {
+				return nil, fmt.Errorf("cannot bind %s: %w", a.Name, err)
+			}
+			other, ok := b.keys[a.Keymap][key]
+			for _, km := range overlaid[a.Keymap] {
+				if !ok {
+					other, ok = b.keys[km][key]
+				}
+			}
+			if ok {
+				conflicts = append(conflicts, fmt.Sprintf("%s is bound to both %s and %s", key, other.Name, a.Name))
+				continue
+			}
+
+			b.keys[a.Keymap][key] = a
+			b.actions[a.Name] = append(b.actions[a.Name], key)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return nil, fmt.Errorf("conflicting key bindings: %s", strings.Join(conflicts, "; "))
+	}
+	return b, nil
+}
+
+// Keys returns the keys bound to an action.
+func (b *Bindings) Keys(name string) []string {
+	return b.actions[name]
+}
+
+// Lookup returns the action bound to a key in the first keymap that binds it.
+func (b *Bindings) Lookup(ek *tcell.EventKey, keymaps ...string) (*Action, bool) {
+	key := eventKey(ek)
+	for _, km := range keymaps {
+		if a, ok := b.keys[km][key]; ok {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// SetBindings replaces the key bindings.
+func (u *UI) SetBindings(b *Bindings) {
+	u.bindings = b
+}
+
+// handleKey runs the action bound to a key in the keymaps that apply to what
+// has focus, unless a command is waiting for a letter.
+func (u *UI) handleKey(ek *tcell.EventKey) bool {
+	if u.letterCmd != nil {
+		u.handleLetter(ek)
+		u.updateScroll()
+		return true
+	}
+
+	keymaps := u.keymaps()
+	u.windowCmd = false
+
+	a, ok := u.bindings.Lookup(ek, keymaps...)
+	if !ok {
+		return false
+	}
+
+	a.Run(u)
+	u.updateScroll()
+	return true
+}
+
+// keymaps returns the keymaps that apply to what has focus, in the order
+// that keys are looked up in them.
+func (u *UI) keymaps() []string {
+	switch {
+	case u.showHelp:
+		return []string{keymapHelp}
+	case u.windowCmd:
+		return []string{keymapWindow}
+	case u.showInspector:
+		return []string{keymapInspector, keymapGlobal}
+	case u.focusSidebar:
+		return []string{keymapSidebar, keymapGlobal}
+	case u.pager.Renderer() != nil:
+		return []string{keymapTable, keymapNormal, keymapGlobal}
+	}
+	return []string{keymapNormal, keymapGlobal}
+}
+
+func concatActions(groups ...[]Action) []Action {
+	all := make([]Action, 0)
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	return all
+}
+
+// eventKey returns the name of a key event, as it is written in bindings.
+func eventKey(ek *tcell.EventKey) string {
+	if ek.Key() == tcell.KeyRune {
+		if ek.Rune() == ' ' {
+			return "Space"
+		}
+		return string(ek.Rune())
+	}
+	return tcell.KeyNames[ek.Key()]
+}
+
+// parseKey returns the name of a key as it is written in bindings: either a
+// single character, or the name of a special key in any case.
+func parseKey(s string) (string, error) {
+	if len([]rune(s)) == 1 {
+		return s, nil
+	}
+	if name, ok := keyNames[strings.ToLower(s)]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown key %q", s)
+}
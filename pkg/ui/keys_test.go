@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewBindingsPresets(t *testing.T) {
+	for name := range presets {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewBindings(name, nil); err != nil {
+				t.Errorf("preset %q: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestNewBindingsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		overrides map[string][]string
+		want      string
+	}{
+		{
+			name:   "unknown preset",
+			preset: "emacs",
+			want:   `unknown key binding preset "emacs"`,
+		},
+		{
+			name:      "unknown action",
+			overrides: map[string][]string{"scroll.nowhere": {"x"}},
+			want:      `cannot bind keys to unknown action "scroll.nowhere"`,
+		},
+		{
+			name:      "unknown key",
+			overrides: map[string][]string{"scroll.top": {"Hyper-X"}},
+			want:      `unknown key "Hyper-X"`,
+		},
+		{
+			name:      "same keymap",
+			overrides: map[string][]string{"scroll.top": {"G"}},
+			want:      "G is bound to both",
+		},
+		{
+			name:      "global and normal",
+			overrides: map[string][]string{"quit": {"G"}},
+			want:      "G is bound to both",
+		},
+		{
+			name:      "table and normal",
+			overrides: map[string][]string{"table.narrow": {"G"}},
+			want:      "G is bound to both",
+		},
+		{
+			name:      "table and normal in a preset",
+			preset:    "less",
+			overrides: map[string][]string{"scroll.top": {"<"}},
+			want:      "< is bound to both",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBindings(tt.preset, tt.overrides)
+			if err == nil {
+				t.Fatal("bindings were accepted")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBindingsModes(t *testing.T) {
+	// Modes hide the keys of the global keymap on purpose.
+	if _, err := NewBindings("", map[string][]string{"sidebar.mute": {"q"}}); err != nil {
+		t.Errorf("key shared by the sidebar and global keymaps: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+
+	"github.com/ripta/axe/pkg/ui/clipboard"
+	"github.com/ripta/axe/pkg/ui/themes"
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+// wheelStep is the number of lines scrolled by one notch of the mouse wheel.
+const wheelStep = 3
+
+// drag tracks the primary mouse button from when it is pressed over a pane
+// until it is released.
+type drag struct {
+	pager *widgets.Pager
+	start int
+	end   int
+	moved bool
+}
+
+// SetView enables the mouse once the application gives the UI its screen,
+// and keeps the screen to draw the help over. Mouse reporting stops the
+// terminal from selecting text, so dragging over lines copies them to the
+// clipboard instead. The theme loses its colours on terminals with too few,
+// which are only known by then.
+func (u *UI) SetView(v views.View) {
+	if s, ok := v.(tcell.Screen); ok {
+		s = themes.Degrade(s)
+		s.EnableMouse()
+		u.locator = widgets.NewLocator(s)
+		v = u.locator
+	}
+	u.view = v
+	u.BoxLayout.SetView(v)
+	u.layoutHelp()
+}
+
+func (u *UI) handleMouseEvent(em *tcell.EventMouse) bool {
+	if u.locator == nil || u.prompting {
+		return false
+	}
+
+	x, y := em.Position()
+	btn := em.Buttons()
+	if u.showHelp {
+		switch {
+		case btn&tcell.WheelUp != 0:
+			u.help.ScrollUp(wheelStep)
+		case btn&tcell.WheelDown != 0:
+			u.help.ScrollDown(wheelStep)
+		}
+		return true
+	}
+	if u.drag != nil {
+		u.dragTo(x, y, btn&tcell.Button1 != 0)
+		return true
+	}
+
+	if u.showSidebar {
+		if _, sy, ok := u.sidebar.Within(u.locator, x, y); ok {
+			switch {
+			case btn&tcell.WheelUp != 0:
+				u.sidebar.SelectPrev()
+			case btn&tcell.WheelDown != 0:
+				u.sidebar.SelectNext()
+			case btn&tcell.Button1 != 0:
+				if u.sidebar.SelectAt(sy) {
+					u.FocusSidebar(true)
+				}
+			}
+			return true
+		}
+	}
+
+	for i, pn := range u.panes {
+		_, py, ok := pn.Pager.Within(u.locator, x, y)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case btn&tcell.WheelUp != 0:
+			pn.Pager.SetFollow(false)
+			pn.Pager.ScrollUp(wheelStep)
+		case btn&tcell.WheelDown != 0:
+			pn.Pager.SetFollow(false)
+			pn.Pager.ScrollDown(wheelStep)
+		case btn&tcell.Button1 != 0:
+			if i != u.focus {
+				u.FocusPane(i)
+			}
+			u.FocusSidebar(false)
+			if idx, ok := pn.Pager.LineAt(py); ok {
+				u.drag = &drag{pager: pn.Pager, start: idx, end: idx}
+				pn.Pager.Mark(idx, idx)
+			}
+		default:
+			return false
+		}
+		u.updateScroll()
+		return true
+	}
+	return false
+}
+
+// dragTo extends the marked lines to the line at a screen position while the
+// button is held down. A release without movement selects the line that was
+// clicked; otherwise, the marked lines are copied.
+func (u *UI) dragTo(x, y int, held bool) {
+	d := u.drag
+	if _, py, ok := d.pager.Within(u.locator, x, y); ok {
+		if idx, ok := d.pager.LineAt(py); ok {
+			d.end = idx
+		}
+	}
+
+	if held {
+		if d.end != d.start {
+			d.moved = true
+		}
+		d.pager.Mark(d.start, d.end)
+		return
+	}
+
+	u.drag = nil
+	lines := d.pager.Marked()
+	d.pager.Unmark()
+	if !d.moved {
+		d.pager.SetFollow(false)
+		d.pager.SetCursor(d.start)
+		u.updateScroll()
+		return
+	}
+
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		texts = append(texts, line.Prefix+line.FullText())
+	}
+	if err := clipboard.Copy(strings.Join(texts, "\n")); err != nil {
+		u.SetMessage(fmt.Sprintf("could not copy lines: %+v", err))
+		return
+	}
+	u.SetMessage(fmt.Sprintf("copied %d lines to clipboard", len(lines)))
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2/views"
+
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+// paneResizeStep is how much the focused pane grows or shrinks, relative to
+// a pane of the default size.
+const paneResizeStep = 0.25
+
+// paneMinWeight keeps panes from being shrunk out of existence.
+const paneMinWeight = 0.25
+
+type pane struct {
+	*widgets.Pane
+	node *paneNode
+}
+
+// paneNode is a node of the pane layout: either a pane, or a split whose
+// children are laid out in its orientation. Splitting a pane in another
+// orientation than its split nests a split in its place, so that each split
+// keeps its own orientation.
+type paneNode struct {
+	parent *paneNode
+	// weight is the size of the node relative to its siblings.
+	weight float64
+
+	pane     *pane
+	orient   views.Orientation
+	children []*paneNode
+}
+
+func newPaneNode(p *widgets.Pane) *paneNode {
+	n := &paneNode{weight: 1}
+	n.pane = &pane{Pane: p, node: n}
+	return n
+}
+
+// leaves appends the panes under the node to panes, in the order they are
+// laid out.
+func (n *paneNode) leaves(panes []*pane) []*pane {
+	if n.pane != nil {
+		return append(panes, n.pane)
+	}
+	for _, c := range n.children {
+		panes = c.leaves(panes)
+	}
+	return panes
+}
+
+// remove removes a child from a split. A split left with a single child is
+// replaced by it, keeping the weight of the split.
+func (n *paneNode) remove(child *paneNode) {
+	for i, c := range n.children {
+		if c == child {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			break
+		}
+	}
+	if len(n.children) != 1 {
+		return
+	}
+
+	c := n.children[0]
+	n.pane, n.orient, n.children = c.pane, c.orient, c.children
+	if n.pane != nil {
+		n.pane.node = n
+	}
+	for _, cc := range n.children {
+		cc.parent = n
+	}
+}
+
+// equalize gives every node under the node the same weight as its siblings.
+func (n *paneNode) equalize() {
+	for _, c := range n.children {
+		c.weight = 1
+		c.equalize()
+	}
+}
+
+// paneActions follow the key pressed after Ctrl-W, in the style of vim
+// window commands.
+var paneActions = []Action{
+	{
+		Name:   "window.split",
+		Keymap: keymapWindow,
+		Help:   "Split the focused pane, stacking the panes",
+		Keys:   []string{"s"},
+		Run:    func(u *UI) { u.SplitPane(views.Vertical) },
+	},
+	{
+		Name:   "window.vsplit",
+		Keymap: keymapWindow,
+		Help:   "Split the focused pane, placing the panes side by side",
+		Keys:   []string{"v"},
+		Run:    func(u *UI) { u.SplitPane(views.Horizontal) },
+	},
+	{
+		Name:   "window.close",
+		Keymap: keymapWindow,
+		Help:   "Close the focused pane",
+		Keys:   []string{"c", "q"},
+		Run:    (*UI).ClosePane,
+	},
+	{
+		Name:   "window.only",
+		Keymap: keymapWindow,
+		Help:   "Close every other pane",
+		Keys:   []string{"o"},
+		Run:    (*UI).OnlyPane,
+	},
+	{
+		Name:   "window.next",
+		Keymap: keymapWindow,
+		Help:   "Focus the next pane",
+		Keys:   []string{"Ctrl-W", "w", "j", "l"},
+		Run:    func(u *UI) { u.FocusPane(u.focus + 1) },
+	},
+	{
+		Name:   "window.prev",
+		Keymap: keymapWindow,
+		Help:   "Focus the previous pane",
+		Keys:   []string{"W", "k", "h"},
+		Run:    func(u *UI) { u.FocusPane(u.focus - 1) },
+	},
+	{
+		Name:   "window.grow",
+		Keymap: keymapWindow,
+		Help:   "Grow the focused pane",
+		Keys:   []string{"+", ">"},
+		Run:    func(u *UI) { u.ResizePane(paneResizeStep) },
+	},
+	{
+		Name:   "window.shrink",
+		Keymap: keymapWindow,
+		Help:   "Shrink the focused pane",
+		Keys:   []string{"-", "<"},
+		Run:    func(u *UI) { u.ResizePane(-paneResizeStep) },
+	},
+	{
+		Name:   "window.equalize",
+		Keymap: keymapWindow,
+		Help:   "Make every pane the same size",
+		Keys:   []string{"="},
+		Run: func(u *UI) {
+			u.paneRoot.equalize()
+			u.layoutPanes()
+		},
+	},
+}
+
+// ClosePane closes the focused pane, unless it is the only one.
+func (u *UI) ClosePane() {
+	if len(u.panes) < 2 {
+		u.SetMessage("cannot close the last pane")
+		return
+	}
+
+	n := u.panes[u.focus].node
+	n.parent.remove(n)
+	u.panes = u.paneRoot.leaves(nil)
+	if u.focus >= len(u.panes) {
+		u.focus = len(u.panes) - 1
+	}
+	u.layoutPanes()
+}
+
+// FocusPane moves focus to the pane at idx, wrapping around at either end.
+func (u *UI) FocusPane(idx int) {
+	n := len(u.panes)
+	u.focus = ((idx % n) + n) % n
+	u.layoutPanes()
+}
+
+// OnlyPane closes every pane except the focused one.
+func (u *UI) OnlyPane() {
+	pn := u.panes[u.focus]
+	u.paneRoot = &paneNode{weight: 1, pane: pn}
+	pn.node = u.paneRoot
+	u.panes = []*pane{pn}
+	u.focus = 0
+	u.layoutPanes()
+}
+
+// ResizePane grows or shrinks the focused pane, relative to the other panes
+// in its split.
+func (u *UI) ResizePane(delta float64) {
+	n := u.panes[u.focus].node
+	n.weight += delta
+	if n.weight < paneMinWeight {
+		n.weight = paneMinWeight
+	}
+	u.layoutPanes()
+}
+
+// SplitPane adds a pane after the focused one, initially showing the same
+// lines, and focuses it. The two are laid out in the given orientation, and
+// other panes keep theirs.
+func (u *UI) SplitPane(orient views.Orientation) {
+	n := u.panes[u.focus].node
+	pn := newPaneNode(widgets.NewPane(u.pager.Split()))
+
+	if p := n.parent; p != nil && p.orient == orient {
+		pn.parent = p
+		for i, c := range p.children {
+			if c == n {
+				p.children = append(p.children[:i+1], append([]*paneNode{pn}, p.children[i+1:]...)...)
+				break
+			}
+		}
+	} else {
+		// The focused pane moves into a new split in its place.
+		old := &paneNode{parent: n, weight: 1, pane: n.pane}
+		old.pane.node = old
+		pn.parent = n
+		n.pane, n.orient, n.children = nil, orient, []*paneNode{old, pn}
+	}
+
+	u.panes = u.paneRoot.leaves(nil)
+	u.focus++
+	u.layoutPanes()
+}
+
+// layoutPanes rebuilds the pane layout after panes are added, removed,
+// resized, or focused.
+func (u *UI) layoutPanes() {
+	// Layouts watch their widgets, so the old ones are emptied before they
+	// are dropped.
+	for _, box := range append(u.splitBoxes, u.paneBox) {
+		for _, w := range box.Widgets() {
+			box.RemoveWidget(w)
+		}
+	}
+	u.splitBoxes = u.splitBoxes[:0]
+	u.paneBox.AddWidget(u.paneWidget(u.paneRoot), 1)
+	for _, pn := range u.panes {
+		pn.ShowTitle(len(u.panes) > 1)
+	}
+
+	u.pager = u.panes[u.focus].Pager
+	u.sidebar.SetSourceState(u.pager.SourceState)
+	u.updateFilters()
+}
+
+// paneWidget returns the widget that lays out a node of the pane layout.
+func (u *UI) paneWidget(n *paneNode) views.Widget {
+	if n.pane != nil {
+		return n.pane
+	}
+	box := views.NewBoxLayout(n.orient)
+	u.splitBoxes = append(u.splitBoxes, box)
+	for _, c := range n.children {
+		box.AddWidget(u.paneWidget(c), c.weight)
+	}
+	return box
+}
+
+// updatePaneTitles describes each pane's follow state and filters in its
+// title, highlighting the focused pane.
+func (u *UI) updatePaneTitles() {
+	for i, pn := range u.panes {
+		state := "NOFOLLOW"
+		if pn.Pager.Following() {
+			state = "FOLLOW"
+		}
+
+		title := fmt.Sprintf(" %d: %s %s", i+1, state, strings.Join(filterNames(pn.Pager), " "))
+		style := u.titleStyle
+		if i == u.focus {
+			style = u.alts.OK.Reverse(true)
+		}
+		pn.SetTitle(title, style)
+	}
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeStep is how far the keys to step through time jump.
+const timeStep = time.Minute
+
+// clockLayouts are the layouts of a time of day, on the local date of the
+// lines.
+var clockLayouts = []string{
+	"15:04",
+	"15:04:05",
+	"15:04:05.999999999",
+}
+
+// dateLayouts are the layouts of a date and time, in local time unless they
+// include a time zone.
+var dateLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
+var timeActions = []Action{
+	{
+		Name:   "time.seek",
+		Keymap: keymapNormal,
+		Help:   "Go to the first line at or after a time",
+		Keys:   []string{"t"},
+		Run: func(u *UI) {
+			u.Prompt("go to time ", func(s string) {
+				if s == "" {
+					return
+				}
+
+				ref, ok := u.pager.TopTime()
+				if !ok {
+					u.SetMessage("no lines to go to")
+					return
+				}
+				t, err := parseTime(s, ref)
+				if err != nil {
+					u.SetMessage(fmt.Sprintf("invalid time: %+v", err))
+					return
+				}
+				u.SeekTime(t)
+			})
+		},
+	},
+	{
+		Name:   "time.back",
+		Keymap: keymapNormal,
+		Help:   "Go back a minute from the top line",
+		Keys:   []string{"["},
+		Run:    func(u *UI) { u.stepTime(-timeStep) },
+	},
+	{
+		Name:   "time.forward",
+		Keymap: keymapNormal,
+		Help:   "Go forward a minute from the top line",
+		Keys:   []string{"]"},
+		Run:    func(u *UI) { u.stepTime(timeStep) },
+	},
+}
+
+// SeekTime scrolls the focused pane to the first line at or after a time.
+func (u *UI) SeekTime(t time.Time) {
+	if u.unfollow().SeekTime(t) {
+		u.updateScroll()
+	}
+}
+
+func (u *UI) stepTime(d time.Duration) {
+	if t, ok := u.pager.TopTime(); ok {
+		u.SeekTime(t.Add(d))
+	}
+}
+
+// parseTime parses a time as typed into the prompt: a time of day on the date
+// of ref, a date and time, or a duration relative to ref with a leading sign,
+// e.g., "14:32", "2020-10-17 14:32:05", or "-5m". Times are in local time, as
+// they are shown, whatever the time zone of the lines.
+func parseTime(s string, ref time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return ref.Add(d), nil
+	}
+
+	for _, layout := range clockLayouts {
+		c, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		y, m, d := ref.In(time.Local).Date()
+		return time.Date(y, m, d, c.Hour(), c.Minute(), c.Second(), c.Nanosecond(), time.Local), nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time of day, a date and time, or a relative duration", s)
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	// The lines are in UTC+9, and the machine is in UTC-7, so the top line at
+	// 01:30 on the 18th in Tokyo is shown at 09:30 on the 17th.
+	local := time.Local
+	time.Local = time.FixedZone("PDT", -7*60*60)
+	defer func() { time.Local = local }()
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	ref := time.Date(2020, 10, 18, 1, 30, 0, 0, tokyo)
+
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{
+			name: "time of day on the local date of ref",
+			in:   "14:32",
+			want: time.Date(2020, 10, 17, 14, 32, 0, 0, time.Local),
+		},
+		{
+			name: "time of day with fractional seconds",
+			in:   "09:30:05.5",
+			want: time.Date(2020, 10, 17, 9, 30, 5, 5e8, time.Local),
+		},
+		{
+			name: "date and time",
+			in:   "2020-10-17 14:32:05",
+			want: time.Date(2020, 10, 17, 14, 32, 5, 0, time.Local),
+		},
+		{
+			name: "date and time with a zone",
+			in:   "2020-10-17T14:32:05Z",
+			want: time.Date(2020, 10, 17, 14, 32, 5, 0, time.UTC),
+		},
+		{
+			name: "relative duration",
+			in:   "-5m",
+			want: ref.Add(-5 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTime(tt.in, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeInvalid(t *testing.T) {
+	ref := time.Date(2020, 10, 17, 14, 32, 0, 0, time.UTC)
+	for _, in := range []string{"", "noon", "25:00", "+5 minutes"} {
+		if _, err := parseTime(in, ref); err == nil {
+			t.Errorf("parseTime(%q) was accepted", in)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+
+	"github.com/ripta/axe/pkg/config"
+	"github.com/ripta/axe/pkg/query"
+	"github.com/ripta/axe/pkg/structstream"
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+const (
+	// tableDefaultWidth is the width of a column that has none, unless it is
+	// the last column, which fills the rest of the screen.
+	tableDefaultWidth = 16
+	// tableMinWidth is the narrowest a column can be resized to.
+	tableMinWidth = 1
+	// tableTimeLayout is how timestamps are shown in the table.
+	tableTimeLayout = "15:04:05.000"
+)
+
+var defaultColumns = []config.Column{
+	{Name: "timestamp", Width: len(tableTimeLayout)},
+	{Name: "pod", Width: 24},
+	{Name: "priority", Width: 8},
+	{Name: "message"},
+}
+
+// Table renders structured lines as aligned columns. Columns are fields of
+// the structured line, as named in queries; other names are KV keys.
+type Table struct {
+	buf      *structstream.Buffer
+	columns  []config.Column
+	selected int
+}
+
+func NewTable(buf *structstream.Buffer, cols []config.Column) *Table {
+	return &Table{
+		buf:     buf,
+		columns: cols,
+	}
+}
+
+// Columns returns the columns of the table.
+func (t *Table) Columns() []config.Column {
+	return t.columns
+}
+
+func (t *Table) Header() (string, int, int) {
+	var hs, he int
+	cells := make([]string, 0, len(t.columns))
+	for i, col := range t.columns {
+		cell := t.fit(i, strings.ToUpper(col.Name))
+		if i == t.selected {
+			hs = len([]rune(strings.Join(cells, " ")))
+			if i > 0 {
+				hs++
+			}
+			he = hs + len([]rune(cell))
+		}
+		cells = append(cells, cell)
+	}
+	return strings.Join(cells, " "), hs, he
+}
+
+func (t *Table) Render(lines []widgets.Line) []string {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	// Visible lines can be far apart in the buffer when filtered, so each is
+	// fetched on its own rather than as a range spanning all of them.
+	rows := make([]string, 0, len(lines))
+	for _, line := range lines {
+		s := t.buf.GetAt(line.Index)
+
+		cells := make([]string, 0, len(t.columns))
+		for i, col := range t.columns {
+			cells = append(cells, t.fit(i, cellValue(line, s, col.Name)))
+		}
+		rows = append(rows, strings.Join(cells, " "))
+	}
+	return rows
+}
+
+// Resize changes the width of the selected column by delta.
+func (t *Table) Resize(delta int) {
+	col := &t.columns[t.selected]
+	if col.Width == 0 {
+		col.Width = tableDefaultWidth
+	}
+
+	col.Width += delta
+	if col.Width < tableMinWidth {
+		col.Width = tableMinWidth
+	}
+}
+
+// Select moves the column selection by delta, wrapping around at either end.
+func (t *Table) Select(delta int) {
+	n := len(t.columns)
+	t.selected = (((t.selected + delta) % n) + n) % n
+}
+
+// SetColumns replaces the columns of the table, keeping the widths of
+// columns that remain.
+func (t *Table) SetColumns(names []string) {
+	widths := make(map[string]int)
+	for _, col := range t.columns {
+		widths[col.Name] = col.Width
+	}
+
+	t.columns = t.columns[:0]
+	for i, name := range names {
+		w, ok := widths[name]
+		if !ok && i < len(names)-1 {
+			w = tableDefaultWidth
+		}
+		t.columns = append(t.columns, config.Column{Name: name, Width: w})
+	}
+	t.selected = 0
+}
+
+// fit truncates or pads a cell to the width of column i. The last column is
+// only constrained if it has an explicit width.
+func (t *Table) fit(i int, s string) string {
+	w := t.columns[i].Width
+	if w == 0 {
+		if i == len(t.columns)-1 {
+			return s
+		}
+		w = tableDefaultWidth
+	}
+	return runewidth.FillRight(runewidth.Truncate(s, w, "…"), w)
+}
+
+func cellValue(line widgets.Line, s structstream.Structline, name string) string {
+	switch name {
+	case "timestamp", "ts", "time":
+		if s.Timestamp.IsZero() {
+			return ""
+		}
+		return s.Timestamp.Local().Format(tableTimeLayout)
+	case "message", "msg":
+		if s.Message == "" {
+			return line.Text
+		}
+		return s.Message
+	}
+
+	v, _ := query.Value(s, name)
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(v)
+}
+
+var tableActions = []Action{
+	{
+		Name:   "table.toggle",
+		Keymap: keymapNormal,
+		Help:   "Show structured fields as a table, or lines as-is",
+		Keys:   []string{"T"},
+		Run:    (*UI).ToggleTable,
+	},
+	{
+		Name:   "table.columns",
+		Keymap: keymapTable,
+		Help:   "Choose the columns, separated by commas",
+		Keys:   []string{"c"},
+		Run: func(u *UI) {
+			u.Prompt("columns ", func(s string) {
+				names := make([]string, 0)
+				for _, name := range strings.Split(s, ",") {
+					if name = strings.TrimSpace(name); name != "" {
+						names = append(names, name)
+					}
+				}
+				if len(names) == 0 {
+					names = columnNames(defaultColumns)
+				}
+
+				u.table.SetColumns(names)
+				u.saveColumns()
+			})
+		},
+	},
+	{
+		Name:   "table.prev-column",
+		Keymap: keymapTable,
+		Help:   "Select the previous column",
+		Keys:   []string{","},
+		Run:    func(u *UI) { u.table.Select(-1) },
+	},
+	{
+		Name:   "table.next-column",
+		Keymap: keymapTable,
+		Help:   "Select the next column",
+		Keys:   []string{"."},
+		Run:    func(u *UI) { u.table.Select(1) },
+	},
+	{
+		Name:   "table.narrow",
+		Keymap: keymapTable,
+		Help:   "Make the selected column narrower",
+		Keys:   []string{"<"},
+		Run: func(u *UI) {
+			u.table.Resize(-1)
+			u.saveColumns()
+		},
+	},
+	{
+		Name:   "table.widen",
+		Keymap: keymapTable,
+		Help:   "Make the selected column wider",
+		Keys:   []string{">"},
+		Run: func(u *UI) {
+			u.table.Resize(1)
+			u.saveColumns()
+		},
+	},
+}
+
+// SetNamespace sets the namespaces being watched, as a single string, and
+// loads the table columns last saved for them.
+func (u *UI) SetNamespace(ns string) {
+	u.namespace = ns
+
+	cols, ok, err := config.LoadColumns(ns)
+	if err != nil {
+		u.SetMessage(fmt.Sprintf("could not load table columns: %+v", err))
+		return
+	}
+	if ok && len(cols) > 0 {
+		u.table = NewTable(u.buffer, cols)
+	}
+}
+
+// ToggleTable switches the focused pane between showing lines as-is and
+// showing their structured fields as a table.
+func (u *UI) ToggleTable() {
+	if u.pager.Renderer() != nil {
+		u.pager.SetRenderer(nil)
+		return
+	}
+	u.pager.SetRenderer(u.table)
+}
+
+func (u *UI) saveColumns() {
+	if err := config.SaveColumns(u.namespace, u.table.Columns()); err != nil {
+		u.SetMessage(fmt.Sprintf("could not save table columns: %+v", err))
+	}
+}
+
+func columnNames(cols []config.Column) []string {
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		names = append(names, col.Name)
+	}
+	return names
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ripta/axe/pkg/config"
+	"github.com/ripta/axe/pkg/structstream"
+	"github.com/ripta/axe/pkg/ui/widgets"
+)
+
+func TestTableRenderSparse(t *testing.T) {
+	parsed := make(map[string]int)
+	tr := func(meta, in string) (structstream.Structline, bool) {
+		parsed[in]++
+		return structstream.PassthruTransformer(meta, in)
+	}
+
+	buf, err := structstream.New(1000, tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		text := "filler"
+		switch i {
+		case 5:
+			text = "first match"
+		case 900:
+			text = "second match"
+		}
+		buf.Append("", text)
+	}
+
+	table := NewTable(buf, []config.Column{{Name: "message"}})
+	rows := table.Render([]widgets.Line{
+		{Index: 5, Text: "first match"},
+		{Index: 900, Text: "second match"},
+	})
+
+	want := []string{"first match", "second match"}
+	if strings.Join(rows, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+	if n := parsed["filler"]; n != 0 {
+		t.Errorf("parsed %d lines between the visible ones, want 0", n)
+	}
+}
+
+func TestTableRenderUnparsable(t *testing.T) {
+	buf, err := structstream.New(10, structstream.LogfmtTransformer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Append("", "msg=one")
+	buf.Append("", "not logfmt")
+	buf.Append("", "msg=three")
+
+	table := NewTable(buf, []config.Column{{Name: "message"}})
+	rows := table.Render([]widgets.Line{
+		{Index: 0, Text: "msg=one"},
+		{Index: 1, Text: "not logfmt"},
+		{Index: 2, Text: "msg=three"},
+	})
+
+	want := []string{"one", "not logfmt", "three"}
+	if strings.Join(rows, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
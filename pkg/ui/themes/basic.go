@@ -0,0 +1,43 @@
+package themes
+
+import "github.com/gdamore/tcell/v2"
+
+// Basic only uses the 16 colours of the terminal's own palette, so that it
+// looks as intended on terminals that support nothing more.
+func Basic() Theme {
+	base := tcell.StyleDefault
+	return Theme{
+		Body: base,
+		Statusbar: Alts{
+			Error:   base.Foreground(tcell.ColorMaroon),
+			Expired: base.Foreground(tcell.ColorMaroon).Reverse(true),
+			New:     base.Foreground(tcell.ColorTeal),
+			Normal:  base,
+			OK:      base.Foreground(tcell.ColorGreen),
+		},
+		Title:  base.Reverse(true),
+		Pager:  base,
+		Legend: base,
+		Priority: Priorities{
+			Debug:   base.Foreground(tcell.ColorGray),
+			Info:    base,
+			Warning: base.Foreground(tcell.ColorOlive),
+			Error:   base.Foreground(tcell.ColorMaroon),
+			Fatal:   base.Foreground(tcell.ColorWhite).Background(tcell.ColorMaroon).Bold(true),
+		},
+		Highlight: Highlights{
+			Match:   base.Reverse(true),
+			Current: base.Foreground(tcell.ColorBlack).Background(tcell.ColorOlive),
+		},
+		Palette: []tcell.Color{
+			tcell.ColorNavy,
+			tcell.ColorTeal,
+			tcell.ColorGreen,
+			tcell.ColorPurple,
+			tcell.ColorOlive,
+			tcell.ColorBlue,
+			tcell.ColorAqua,
+			tcell.ColorFuchsia,
+		},
+	}
+}
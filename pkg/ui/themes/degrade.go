@@ -0,0 +1,29 @@
+package themes
+
+import "github.com/gdamore/tcell/v2"
+
+// minColors is the fewest colours that a theme is drawn with. On terminals
+// with fewer, styles keep their attributes but lose their colours.
+const minColors = 8
+
+// monochrome is a screen that draws every cell in the default colours.
+type monochrome struct {
+	tcell.Screen
+}
+
+// Degrade wraps a screen so that themes are drawn without colour if the
+// terminal has fewer than minColors, where the nearest colour in its palette
+// would more often hide text than show it. tcell already maps true colours to
+// the nearest colour in larger palettes. The number of colours is only known
+// once the screen is initialized.
+func Degrade(s tcell.Screen) tcell.Screen {
+	if s.Colors() >= minColors {
+		return s
+	}
+	return monochrome{Screen: s}
+}
+
+func (m monochrome) SetContent(x, y int, ch rune, comb []rune, style tcell.Style) {
+	style = style.Foreground(tcell.ColorDefault).Background(tcell.ColorDefault)
+	m.Screen.SetContent(x, y, ch, comb, style)
+}
@@ -0,0 +1,240 @@
+package themes
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// Load reads a theme from a YAML file; TOML is not supported. A theme file starts from the built-in
+// theme named by its "base" key, or the default theme, and replaces the
+// elements that it sets, for example:
+//
+//	base: solarized-light
+//	statusbar:
+//	  error: {fg: "#ff0000", bold: true}
+//	priority:
+//	  debug: {fg: gray, dim: true}
+//	palette: ["#268bd2", teal, 208]
+//
+// Styles are maps of "fg" and "bg" colours, which are names, "#rrggbb" hex
+// values, palette indices, or "default", and of attributes that are turned on
+// or off. Errors name the key that is invalid.
+func Load(path string) (Theme, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	t, err := Parse(bs)
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Parse decodes a theme in the format read by Load.
+func Parse(bs []byte) (Theme, error) {
+	doc := make(map[string]interface{})
+	if err := yaml.Unmarshal(bs, &doc); err != nil {
+		return Theme{}, err
+	}
+
+	base := DefaultName
+	if v, ok := doc["base"]; ok {
+		name, ok := v.(string)
+		if !ok {
+			return Theme{}, fmt.Errorf("base: expected the name of a theme, got %v", v)
+		}
+		base = name
+	}
+
+	t, ok := Builtin(base)
+	if !ok {
+		return Theme{}, fmt.Errorf("base: unknown theme %q, expected one of %s", base, strings.Join(Names(), ", "))
+	}
+
+	d := &decoder{styles: t.styles()}
+	for _, k := range sortedKeys(doc) {
+		switch k {
+		case "base":
+		case "palette":
+			t.Palette = d.palette(k, doc[k])
+		default:
+			d.element(k, doc[k])
+		}
+	}
+
+	if len(d.errs) > 0 {
+		return Theme{}, errors.New(strings.Join(d.errs, "; "))
+	}
+	return t, nil
+}
+
+// styles returns the styles of the theme by their key in theme files.
+func (t *Theme) styles() map[string]*tcell.Style {
+	return map[string]*tcell.Style{
+		"body":              &t.Body,
+		"title":             &t.Title,
+		"pager":             &t.Pager,
+		"legend":            &t.Legend,
+		"statusbar.error":   &t.Statusbar.Error,
+		"statusbar.expired": &t.Statusbar.Expired,
+		"statusbar.new":     &t.Statusbar.New,
+		"statusbar.normal":  &t.Statusbar.Normal,
+		"statusbar.ok":      &t.Statusbar.OK,
+		"priority.debug":    &t.Priority.Debug,
+		"priority.info":     &t.Priority.Info,
+		"priority.warning":  &t.Priority.Warning,
+		"priority.error":    &t.Priority.Error,
+		"priority.fatal":    &t.Priority.Fatal,
+		"highlight.match":   &t.Highlight.Match,
+		"highlight.current": &t.Highlight.Current,
+	}
+}
+
+var attrs = map[string]func(tcell.Style, bool) tcell.Style{
+	"blink":         tcell.Style.Blink,
+	"bold":          tcell.Style.Bold,
+	"dim":           tcell.Style.Dim,
+	"italic":        tcell.Style.Italic,
+	"reverse":       tcell.Style.Reverse,
+	"strikethrough": tcell.Style.StrikeThrough,
+	"underline":     tcell.Style.Underline,
+}
+
+// attrNames lists the attributes for error messages.
+var attrNames = func() string {
+	names := make([]string, 0, len(attrs))
+	for k := range attrs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}()
+
+// decoder applies the elements of a theme file to the styles of a theme,
+// collecting an error for each invalid key.
+type decoder struct {
+	styles map[string]*tcell.Style
+	errs   []string
+}
+
+func (d *decoder) errorf(key, format string, args ...interface{}) {
+	d.errs = append(d.errs, key+": "+fmt.Sprintf(format, args...))
+}
+
+// element decodes the style at key, or the group of styles under it.
+func (d *decoder) element(key string, v interface{}) {
+	if s, ok := d.styles[key]; ok {
+		*s = d.style(key, v, *s)
+		return
+	}
+
+	group := false
+	for k := range d.styles {
+		if strings.HasPrefix(k, key+".") {
+			group = true
+		}
+	}
+	if !group {
+		d.errorf(key, "unknown element")
+		return
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		d.errorf(key, "expected a map of elements, got %v", v)
+		return
+	}
+	for _, k := range sortedKeys(m) {
+		d.element(key+"."+k, m[k])
+	}
+}
+
+func (d *decoder) palette(key string, v interface{}) []tcell.Color {
+	vs, ok := v.([]interface{})
+	if !ok || len(vs) == 0 {
+		d.errorf(key, "expected a list of colours, got %v", v)
+		return nil
+	}
+
+	cs := make([]tcell.Color, 0, len(vs))
+	for i, cv := range vs {
+		if c, ok := d.color(fmt.Sprintf("%s[%d]", key, i), cv); ok {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+func (d *decoder) style(key string, v interface{}, s tcell.Style) tcell.Style {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		d.errorf(key, "expected a style, got %v", v)
+		return s
+	}
+
+	for _, k := range sortedKeys(m) {
+		switch k {
+		case "fg":
+			if c, ok := d.color(key+".fg", m[k]); ok {
+				s = s.Foreground(c)
+			}
+		case "bg":
+			if c, ok := d.color(key+".bg", m[k]); ok {
+				s = s.Background(c)
+			}
+		default:
+			fn, ok := attrs[k]
+			if !ok {
+				d.errorf(key+"."+k, "unknown attribute, expected fg, bg, or one of %s", attrNames)
+				continue
+			}
+			on, ok := m[k].(bool)
+			if !ok {
+				d.errorf(key+"."+k, "expected true or false, got %v", m[k])
+				continue
+			}
+			s = fn(s, on)
+		}
+	}
+	return s
+}
+
+func (d *decoder) color(key string, v interface{}) (tcell.Color, bool) {
+	switch cv := v.(type) {
+	case float64:
+		if cv != float64(int(cv)) || cv < 0 || cv > 255 {
+			d.errorf(key, "palette index %v is not between 0 and 255", cv)
+			return tcell.ColorDefault, false
+		}
+		return tcell.PaletteColor(int(cv)), true
+	case string:
+		name := strings.ToLower(cv)
+		if name == "default" {
+			return tcell.ColorDefault, true
+		}
+		if c := tcell.GetColor(name); c != tcell.ColorDefault {
+			return c, true
+		}
+		d.errorf(key, "unknown colour %q", cv)
+	default:
+		d.errorf(key, "expected a colour, got %v", v)
+	}
+	return tcell.ColorDefault, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
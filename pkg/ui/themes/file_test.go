@@ -0,0 +1,180 @@
+package themes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParse(t *testing.T) {
+	th, err := Parse([]byte(`
+base: basic
+pager: {fg: "#ffffff", bg: black}
+legend: {bg: 236}
+statusbar:
+  error: {fg: red, bold: true}
+priority:
+  debug: {dim: true}
+palette: ["#268bd2", teal, 208]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := Basic()
+	if want := tcell.StyleDefault.Foreground(tcell.GetColor("#ffffff")).Background(tcell.ColorBlack); th.Pager != want {
+		t.Errorf("pager = %v, want %v", th.Pager, want)
+	}
+	if want := tcell.StyleDefault.Background(tcell.PaletteColor(236)); th.Legend != want {
+		t.Errorf("legend = %v, want %v", th.Legend, want)
+	}
+	if want := base.Statusbar.Error.Foreground(tcell.ColorRed).Bold(true); th.Statusbar.Error != want {
+		t.Errorf("statusbar.error = %v, want %v", th.Statusbar.Error, want)
+	}
+	if want := base.Priority.Debug.Dim(true); th.Priority.Debug != want {
+		t.Errorf("priority.debug = %v, want %v", th.Priority.Debug, want)
+	}
+	if th.Statusbar.OK != base.Statusbar.OK {
+		t.Errorf("statusbar.ok was changed from the base theme")
+	}
+	want := []tcell.Color{tcell.GetColor("#268bd2"), tcell.ColorTeal, tcell.PaletteColor(208)}
+	if len(th.Palette) != len(want) {
+		t.Fatalf("palette = %v, want %v", th.Palette, want)
+	}
+	for i := range want {
+		if th.Palette[i] != want[i] {
+			t.Errorf("palette[%d] = %v, want %v", i, th.Palette[i], want[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{
+			name: "unknown base",
+			in:   `base: nope`,
+			want: []string{`base: unknown theme "nope"`},
+		},
+		{
+			name: "base that is not a name",
+			in:   `base: [basic]`,
+			want: []string{"base: expected the name of a theme"},
+		},
+		{
+			name: "unknown element",
+			in:   `sidebar: {fg: red}`,
+			want: []string{"sidebar: unknown element"},
+		},
+		{
+			name: "unknown element in a group",
+			in:   "statusbar:\n  warning: {fg: red}",
+			want: []string{"statusbar.warning: unknown element"},
+		},
+		{
+			name: "group that is not a map",
+			in:   `priority: red`,
+			want: []string{"priority: expected a map of elements"},
+		},
+		{
+			name: "style that is not a map",
+			in:   `title: red`,
+			want: []string{"title: expected a style"},
+		},
+		{
+			name: "unknown colour name",
+			in:   `title: {fg: reddish}`,
+			want: []string{`title.fg: unknown colour "reddish"`},
+		},
+		{
+			name: "bad hex colour",
+			in:   `legend: {bg: "#12345"}`,
+			want: []string{`legend.bg: unknown colour "#12345"`},
+		},
+		{
+			name: "palette index out of range",
+			in:   `pager: {fg: 256}`,
+			want: []string{"pager.fg: palette index 256 is not between 0 and 255"},
+		},
+		{
+			name: "fractional palette index",
+			in:   `pager: {fg: 1.5}`,
+			want: []string{"pager.fg: palette index 1.5 is not between 0 and 255"},
+		},
+		{
+			name: "colour of the wrong type",
+			in:   `pager: {fg: true}`,
+			want: []string{"pager.fg: expected a colour"},
+		},
+		{
+			name: "unknown attribute",
+			in:   `title: {shiny: true}`,
+			want: []string{"title.shiny: unknown attribute"},
+		},
+		{
+			name: "attribute that is not a boolean",
+			in:   `title: {bold: yes please}`,
+			want: []string{"title.bold: expected true or false"},
+		},
+		{
+			name: "empty palette",
+			in:   `palette: []`,
+			want: []string{"palette: expected a list of colours"},
+		},
+		{
+			name: "bad colour in the palette",
+			in:   `palette: [red, nope]`,
+			want: []string{`palette[1]: unknown colour "nope"`},
+		},
+		{
+			name: "every invalid key is reported",
+			in:   "title: {fg: nope}\nbody: {bold: 1}",
+			want: []string{"body.bold: expected true or false", `title.fg: unknown colour "nope"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.in))
+			if err == nil {
+				t.Fatal("theme was accepted")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "axe-themes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bad.yaml")
+	if err := ioutil.WriteFile(path, []byte(`title: {fg: nope}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(path)
+	if err == nil {
+		t.Fatal("theme was accepted")
+	}
+	if want := path + `: title.fg: unknown colour "nope"`; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); !os.IsNotExist(err) {
+		t.Errorf("error for a missing file = %v, want one that it does not exist", err)
+	}
+}
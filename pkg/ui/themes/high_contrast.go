@@ -0,0 +1,43 @@
+package themes
+
+import "github.com/gdamore/tcell/v2"
+
+// HighContrast draws bright colours and bold text on black, for readability
+// over colour fidelity.
+func HighContrast() Theme {
+	base := tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
+	return Theme{
+		Body: base,
+		Statusbar: Alts{
+			Error:   base.Foreground(tcell.ColorRed).Bold(true),
+			Expired: base.Foreground(tcell.ColorRed).Reverse(true).Bold(true),
+			New:     base.Foreground(tcell.ColorAqua).Bold(true),
+			Normal:  base.Bold(true),
+			OK:      base.Foreground(tcell.ColorLime).Bold(true),
+		},
+		Title:  base.Reverse(true).Bold(true),
+		Pager:  base,
+		Legend: base,
+		Priority: Priorities{
+			Debug:   base.Foreground(tcell.ColorSilver),
+			Info:    base,
+			Warning: base.Foreground(tcell.ColorYellow).Bold(true),
+			Error:   base.Foreground(tcell.ColorRed).Bold(true),
+			Fatal:   base.Foreground(tcell.ColorWhite).Background(tcell.ColorRed).Bold(true),
+		},
+		Highlight: Highlights{
+			Match:   base.Reverse(true),
+			Current: base.Foreground(tcell.ColorBlack).Background(tcell.ColorYellow).Bold(true),
+		},
+		Palette: []tcell.Color{
+			tcell.ColorAqua,
+			tcell.ColorFuchsia,
+			tcell.ColorLime,
+			tcell.ColorOrange,
+			tcell.ColorSkyblue,
+			tcell.ColorYellow,
+			tcell.ColorPink,
+			tcell.ColorWhite,
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package themes
+
+import "github.com/gdamore/tcell/v2"
+
+// MonochromeName is the name of the theme used when the NO_COLOR environment
+// variable is set.
+const MonochromeName = "monochrome"
+
+// Monochrome uses no colours at all, only bold, reverse, and underline, for
+// users who ask for no colour and for terminals that have none. Log sources
+// are not told apart by colour.
+func Monochrome() Theme {
+	base := tcell.StyleDefault
+	return Theme{
+		Body: base,
+		Statusbar: Alts{
+			Error:   base.Bold(true).Reverse(true),
+			Expired: base.Reverse(true),
+			New:     base.Underline(true),
+			Normal:  base,
+			OK:      base.Bold(true),
+		},
+		Title:  base.Reverse(true),
+		Pager:  base,
+		Legend: base,
+		Priority: Priorities{
+			Debug:   base,
+			Info:    base,
+			Warning: base.Bold(true),
+			Error:   base.Bold(true).Underline(true),
+			Fatal:   base.Bold(true).Reverse(true),
+		},
+		Highlight: Highlights{
+			Match:   base.Underline(true),
+			Current: base.Reverse(true),
+		},
+	}
+}
@@ -28,6 +28,8 @@ var (
 
 func SolarizedDark() Theme {
 	base := tcell.StyleDefault.Background(solarizedBackground).Foreground(solarizedForeground)
+	// Log lines are drawn over the terminal's own background.
+	line := tcell.StyleDefault
 	return Theme{
 		Body: base,
 		Statusbar: Alts{
@@ -37,6 +39,29 @@ func SolarizedDark() Theme {
 			Normal:  base,
 			OK:      base.Foreground(solarizedGreen),
 		},
-		Title: base.Background(solarizedSelected),
+		Title:  base.Background(solarizedSelected),
+		Pager:  line,
+		Legend: line,
+		Priority: Priorities{
+			Debug:   line.Foreground(solarizedGray1),
+			Info:    line,
+			Warning: line.Foreground(solarizedYellow),
+			Error:   line.Foreground(solarizedRed),
+			Fatal:   line.Foreground(solarizedWhite).Background(solarizedRed).Bold(true),
+		},
+		Highlight: Highlights{
+			Match:   line.Reverse(true),
+			Current: line.Background(tcell.ColorYellow),
+		},
+		Palette: []tcell.Color{
+			solarizedBlue,
+			solarizedCyan,
+			solarizedGreen,
+			solarizedMagenta,
+			solarizedOrange,
+			solarizedPurple,
+			solarizedYellow,
+			solarizedZeta,
+		},
 	}
 }
@@ -0,0 +1,49 @@
+package themes
+
+import "github.com/gdamore/tcell/v2"
+
+var (
+	solarizedLightForeground = solarizedGray2
+	solarizedLightBackground = solarizedWhite
+	solarizedLightSelected   = tcell.GetColor("#eee8d5")
+)
+
+func SolarizedLight() Theme {
+	base := tcell.StyleDefault.Background(solarizedLightBackground).Foreground(solarizedLightForeground)
+	// Log lines are drawn over the terminal's own background.
+	line := tcell.StyleDefault
+	return Theme{
+		Body: base,
+		Statusbar: Alts{
+			Error:   base.Foreground(solarizedRed),
+			Expired: base.Foreground(solarizedRed).Reverse(true),
+			New:     base.Foreground(solarizedBlue),
+			Normal:  base,
+			OK:      base.Foreground(solarizedGreen),
+		},
+		Title:  base.Background(solarizedLightSelected),
+		Pager:  line,
+		Legend: line,
+		Priority: Priorities{
+			Debug:   line.Foreground(solarizedGray4),
+			Info:    line,
+			Warning: line.Foreground(solarizedYellow),
+			Error:   line.Foreground(solarizedRed),
+			Fatal:   line.Foreground(solarizedWhite).Background(solarizedRed).Bold(true),
+		},
+		Highlight: Highlights{
+			Match:   line.Reverse(true),
+			Current: line.Background(solarizedYellow).Foreground(solarizedWhite),
+		},
+		Palette: []tcell.Color{
+			solarizedBlue,
+			solarizedCyan,
+			solarizedGreen,
+			solarizedMagenta,
+			solarizedOrange,
+			solarizedPurple,
+			solarizedYellow,
+			solarizedRed,
+		},
+	}
+}
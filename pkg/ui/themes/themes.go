@@ -1,7 +1,11 @@
 package themes
 
 import (
+	"sort"
+
 	"github.com/gdamore/tcell/v2"
+
+	"github.com/ripta/axe/pkg/structstream"
 )
 
 type AltType string
@@ -37,8 +41,81 @@ func (a Alts) Select(t AltType) tcell.Style {
 	}
 }
 
+// Priorities are the styles of log lines, by their parsed severity.
+type Priorities struct {
+	Debug   tcell.Style
+	Info    tcell.Style
+	Warning tcell.Style
+	Error   tcell.Style
+	Fatal   tcell.Style
+}
+
+// Select returns the style for a severity. Trace lines are styled as debug
+// lines, and lines of unknown severity as informational.
+func (p Priorities) Select(sev structstream.Severity) tcell.Style {
+	switch sev {
+	case structstream.SeverityTrace, structstream.SeverityDebug:
+		return p.Debug
+	case structstream.SeverityWarning:
+		return p.Warning
+	case structstream.SeverityError:
+		return p.Error
+	case structstream.SeverityFatal:
+		return p.Fatal
+	default:
+		return p.Info
+	}
+}
+
+// Highlights are the styles of keyword matches in log lines.
+type Highlights struct {
+	Match   tcell.Style
+	Current tcell.Style
+}
+
 type Theme struct {
-	Body      tcell.Style
-	Title     tcell.Style
+	Body  tcell.Style
+	Title tcell.Style
+	// Pager is the style of panes of log lines where there is no line, and
+	// of the prefixes of lines, which are coloured from the palette.
+	Pager tcell.Style
+	// Legend is the style of the panel listing the colour of each source.
+	Legend    tcell.Style
 	Statusbar Alts
+	Priority  Priorities
+	Highlight Highlights
+
+	// Palette holds the colours assigned to log sources, which should be
+	// distinguishable from each other against the body background.
+	Palette []tcell.Color
+}
+
+// DefaultName is the name of the theme used when none is chosen.
+const DefaultName = "solarized-dark"
+
+var builtins = map[string]func() Theme{
+	"basic":           Basic,
+	"high-contrast":   HighContrast,
+	MonochromeName:    Monochrome,
+	"solarized-dark":  SolarizedDark,
+	"solarized-light": SolarizedLight,
+}
+
+// Builtin returns the built-in theme with a name.
+func Builtin(name string) (Theme, bool) {
+	fn, ok := builtins[name]
+	if !ok {
+		return Theme{}, false
+	}
+	return fn(), true
+}
+
+// Names returns the names of the built-in themes, in order.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
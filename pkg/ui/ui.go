@@ -1,39 +1,146 @@
 package ui
 
 import (
+	"fmt"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/gdamore/tcell/v2/views"
 
+	"github.com/ripta/axe/pkg/config"
+	"github.com/ripta/axe/pkg/kubelogs"
+	"github.com/ripta/axe/pkg/query"
+	"github.com/ripta/axe/pkg/structstream"
 	"github.com/ripta/axe/pkg/ui/themes"
 	"github.com/ripta/axe/pkg/ui/widgets"
 )
 
+// hscrollStep is the number of columns scrolled horizontally at a time.
+const hscrollStep = 8
+
+// thresholds are the minimum severities that the user can cycle through. The
+// unknown severity shows all lines, including those without a severity.
+var thresholds = []structstream.Severity{
+	structstream.SeverityUnknown,
+	structstream.SeverityDebug,
+	structstream.SeverityInfo,
+	structstream.SeverityWarning,
+	structstream.SeverityError,
+	structstream.SeverityFatal,
+}
+
+// stateAlts are the statusbar styles used to draw container states.
+var stateAlts = map[kubelogs.ContainerState]themes.AltType{
+	kubelogs.ContainerStateWaiting:    themes.AltTypeNew,
+	kubelogs.ContainerStateStreaming:  themes.AltTypeOK,
+	kubelogs.ContainerStateBackoff:    themes.AltTypeError,
+	kubelogs.ContainerStateTerminated: themes.AltTypeExpired,
+}
+
 type UI struct {
 	views.BoxLayout
 
-	app       *views.Application
-	statusbar *widgets.Statusbar
+	app        *views.Application
+	body       *views.BoxLayout
+	buffer     *structstream.Buffer
+	input      *widgets.Input
+	scrollback *widgets.Scrollback
+	statusbar  *widgets.Statusbar
+
+	alts         themes.Alts
+	bookmarks    *widgets.Bookmarks
+	bookmarkList *widgets.BookmarkList
+	colors       *Colorizer
+	detector     *structstream.Detector
+	help         *widgets.Help
+	inspector    *widgets.Inspector
+	legend       *widgets.Legend
+	podInfo      PodInfoFunc
+	priorities   themes.Priorities
+	sidebar      *widgets.Sidebar
+	table        *Table
+	titleStyle   tcell.Style
+
+	bindings *Bindings
+	// letterCmd is the command waiting for the letter of a bookmark, if any.
+	letterCmd func(u *UI, letter rune)
+
+	// view is the whole screen, which the help is drawn over; locator is
+	// the screen, once the application has set it, and drag is the mouse
+	// drag in progress, if any.
+	view    views.View
+	locator *widgets.Locator
+	drag    *drag
+
+	// namespace identifies the set of watched namespaces, under which
+	// settings like table columns are saved.
+	namespace string
+
+	// pager is the pager in the focused pane.
+	focus   int
+	pager   *widgets.Pager
+	paneBox *views.BoxLayout
+	// paneRoot is the pane layout, and panes are its panes in order.
+	paneRoot *paneNode
+	panes    []*pane
+	// splitBoxes are the layouts of the splits in the pane layout.
+	splitBoxes []*views.BoxLayout
+
+	// held are the lines received while paused, which are appended when
+	// resumed.
+	held []widgets.Line
 
-	autoscroll bool
-	pager      *widgets.Pager
+	focusSidebar  bool
+	paused        bool
+	prompting     bool
+	showBookmarks bool
+	showHelp      bool
+	showInspector bool
+	showLegend    bool
+	showSidebar   bool
+	windowCmd     bool
 }
 
-func New(app *views.Application, style themes.Theme) *UI {
-	pg := widgets.NewPager(app)
+func New(app *views.Application, style themes.Theme, buf *structstream.Buffer) *UI {
+	scrollback := widgets.NewScrollback()
 
 	sb := widgets.NewStatusbar(app, style)
 	sb.SetStatus("START-UP", themes.AltTypeError)
 
 	u := &UI{
-		app:       app,
-		statusbar: sb,
+		app:        app,
+		body:       views.NewBoxLayout(views.Horizontal),
+		buffer:     buf,
+		input:      widgets.NewInput(style.Statusbar.Normal),
+		scrollback: scrollback,
+		statusbar:  sb,
 
-		autoscroll: true,
-		pager:      pg,
+		alts:         style.Statusbar,
+		bookmarks:    widgets.NewBookmarks(),
+		bookmarkList: widgets.NewBookmarkList("Bookmarks", style.Body, scrollback),
+		colors:       NewColorizer(style.Pager, style.Palette),
+		help:         widgets.NewHelp("Keys", style.Body, style.Statusbar.New),
+		inspector:    widgets.NewInspector(style.Body, style.Statusbar.New),
+		legend:       widgets.NewLegend("Sources", style.Legend),
+		priorities:   style.Priority,
+		sidebar:      widgets.NewSidebar(style.Body),
+		table:        NewTable(buf, append([]config.Column(nil), defaultColumns...)),
+		titleStyle:   style.Title,
+
+		paneBox:  views.NewBoxLayout(views.Vertical),
+		paneRoot: newPaneNode(widgets.NewPane(widgets.NewPager(app, scrollback))),
 	}
+	u.panes = u.paneRoot.leaves(nil)
+
+	// The default bindings never conflict.
+	u.bindings, _ = NewBindings("", nil)
+
+	u.body.AddWidget(u.paneBox, 1)
+	u.layoutPanes()
+	u.pager.SetStyle(style.Pager)
+	u.pager.SetMatchStyles(style.Highlight.Match, style.Highlight.Current)
 
 	u.SetOrientation(views.Vertical)
-	u.AddWidget(pg, 1)
+	u.AddWidget(u.body, 1)
 	u.AddWidget(sb, 0)
 	return u
 }
@@ -41,83 +148,522 @@ func New(app *views.Application, style themes.Theme) *UI {
 func (u *UI) HandleEvent(e tcell.Event) bool {
 	switch te := e.(type) {
 	case *tcell.EventKey:
-		return u.handleAppEventKeys(te) || u.handleScrollEventKeys(te)
+		if u.prompting {
+			return u.input.HandleEvent(te)
+		}
+		return u.handleKey(te)
+	case *tcell.EventMouse:
+		return u.handleMouseEvent(te)
 	}
 	return false
 }
 
-func (u *UI) handleAppEventKeys(ek *tcell.EventKey) bool {
-	switch ek.Key() {
-	case tcell.KeyCtrlC:
-		u.app.Quit()
-		return true
-	case tcell.KeyRune:
-		switch ek.Rune() {
-		case 'q':
-			u.app.Quit()
-			return true
-		}
+var appActions = []Action{
+	{
+		Name:   "quit",
+		Keymap: keymapGlobal,
+		Help:   "Quit",
+		Keys:   []string{"q", "Ctrl-C"},
+		Run:    func(u *UI) { u.app.Quit() },
+	},
+	{
+		Name:   "legend.toggle",
+		Keymap: keymapGlobal,
+		Help:   "Show or hide the legend of source colours",
+		Keys:   []string{"L"},
+		Run:    (*UI).ToggleLegend,
+	},
+	{
+		Name:   "sidebar.toggle",
+		Keymap: keymapGlobal,
+		Help:   "Show or hide the sidebar of containers",
+		Keys:   []string{"S"},
+		Run:    (*UI).ToggleSidebar,
+	},
+	{
+		Name:   "sidebar.focus",
+		Keymap: keymapGlobal,
+		Help:   "Move focus to or from the sidebar",
+		Keys:   []string{"Tab"},
+		Run:    func(u *UI) { u.FocusSidebar(!u.focusSidebar) },
+	},
+	{
+		Name:   "pause.toggle",
+		Keymap: keymapGlobal,
+		Help:   "Pause or resume adding new lines to the view",
+		Keys:   []string{"P"},
+		Run:    (*UI).TogglePause,
+	},
+	{
+		Name:   "window.command",
+		Keymap: keymapGlobal,
+		Help:   "Start a window command, as in vim",
+		Keys:   []string{"Ctrl-W"},
+		Run:    func(u *UI) { u.windowCmd = true },
+	},
+}
+
+var filterActions = []Action{
+	{
+		Name:   "filter.regexp",
+		Keymap: keymapNormal,
+		Help:   "Add a regexp filter, or an exclusion with a leading !",
+		Keys:   []string{"&"},
+		Run: func(u *UI) {
+			// As in less(1), a leading '!' shows only non-matching lines. An
+			// empty filter removes the most recently added one.
+			u.Prompt("&", func(expr string) {
+				if expr == "" {
+					u.PopFilter()
+					return
+				}
+
+				f, err := widgets.NewRegexpFilter(expr)
+				if err != nil {
+					u.SetMessage(fmt.Sprintf("invalid filter: %+v", err))
+					return
+				}
+				u.PushFilter(f)
+			})
+		},
+	},
+	{
+		Name:   "filter.where",
+		Keymap: keymapNormal,
+		Help:   "Add a query filter on structured fields",
+		Keys:   []string{"w"},
+		Run: func(u *UI) {
+			// Queries are evaluated against the structured form of each line.
+			// An empty query removes the most recently added filter.
+			u.Prompt("where ", func(expr string) {
+				if expr == "" {
+					u.PopFilter()
+					return
+				}
+
+				q, err := query.Parse(expr)
+				if err != nil {
+					u.SetMessage(fmt.Sprintf("invalid query: %+v", err))
+					return
+				}
+				u.PushFilter(NewQueryFilter(u.buffer, q))
+			})
+		},
+	},
+	{
+		Name:   "filter.threshold",
+		Keymap: keymapNormal,
+		Help:   "Cycle the minimum severity of lines shown",
+		Keys:   []string{"p"},
+		Run:    (*UI).CycleThreshold,
+	},
+	{
+		Name:   "search",
+		Keymap: keymapNormal,
+		Help:   "Highlight a keyword and jump to its first match",
+		Keys:   []string{"/"},
+		Run: func(u *UI) {
+			u.Prompt("/", func(kw string) {
+				u.pager.SetKeyword(kw)
+				if kw != "" && !u.pager.HighlightNext() {
+					u.SetMessage(fmt.Sprintf("pattern not found: %s", kw))
+				}
+			})
+		},
+	},
+	{
+		Name:   "search.next",
+		Keymap: keymapNormal,
+		Help:   "Jump to the next match of the keyword",
+		Keys:   []string{"n"},
+		Run:    func(u *UI) { u.searchJump((*widgets.Pager).HighlightNext) },
+	},
+	{
+		Name:   "search.prev",
+		Keymap: keymapNormal,
+		Help:   "Jump to the previous match of the keyword",
+		Keys:   []string{"N"},
+		Run:    func(u *UI) { u.searchJump((*widgets.Pager).HighlightPrev) },
+	},
+}
+
+var scrollActions = []Action{
+	{
+		Name:   "cursor.down",
+		Keymap: keymapNormal,
+		Help:   "Select the next line",
+		Keys:   []string{"Down"},
+		Run:    func(u *UI) { u.unfollow().MoveCursor(1) },
+	},
+	{
+		Name:   "cursor.up",
+		Keymap: keymapNormal,
+		Help:   "Select the previous line",
+		Keys:   []string{"Up"},
+		Run:    func(u *UI) { u.unfollow().MoveCursor(-1) },
+	},
+	{
+		Name:   "inspect",
+		Keymap: keymapNormal,
+		Help:   "Inspect the fields of the selected line",
+		Keys:   []string{"Enter"},
+		Run:    func(u *UI) { u.unfollow(); u.Inspect() },
+	},
+	{
+		Name:   "event.toggle",
+		Keymap: keymapNormal,
+		Help:   "Expand or collapse the lines of the selected multi-line event",
+		Keys:   []string{"o"},
+		Run:    (*UI).ToggleEvent,
+	},
+	{
+		Name:   "follow.toggle",
+		Keymap: keymapNormal,
+		Help:   "Follow new lines, or stop following",
+		Keys:   []string{"f"},
+		Run:    func(u *UI) { u.pager.SetFollow(!u.pager.Following()) },
+	},
+	{
+		Name:   "scroll.line-down",
+		Keymap: keymapNormal,
+		Help:   "Scroll down a line",
+		Keys:   []string{"Ctrl-E"},
+		Run:    func(u *UI) { u.unfollow().ScrollDown(1) },
+	},
+	{
+		Name:   "scroll.line-up",
+		Keymap: keymapNormal,
+		Help:   "Scroll up a line",
+		Keys:   []string{"Ctrl-Y"},
+		Run:    func(u *UI) { u.unfollow().ScrollUp(1) },
+	},
+	{
+		Name:   "scroll.half-page-down",
+		Keymap: keymapNormal,
+		Help:   "Scroll down half a screen",
+		Keys:   []string{"j", "Ctrl-D"},
+		Run:    func(u *UI) { u.unfollow().ScrollPageDown(1) },
+	},
+	{
+		Name:   "scroll.half-page-up",
+		Keymap: keymapNormal,
+		Help:   "Scroll up half a screen",
+		Keys:   []string{"k", "Ctrl-U"},
+		Run:    func(u *UI) { u.unfollow().ScrollPageUp(1) },
+	},
+	{
+		Name:   "scroll.page-down",
+		Keymap: keymapNormal,
+		Help:   "Scroll down a screen",
+		Keys:   []string{"PgDn"},
+		Run:    func(u *UI) { u.unfollow().ScrollPageDown(2) },
+	},
+	{
+		Name:   "scroll.page-up",
+		Keymap: keymapNormal,
+		Help:   "Scroll up a screen",
+		Keys:   []string{"PgUp"},
+		Run:    func(u *UI) { u.unfollow().ScrollPageUp(2) },
+	},
+	{
+		Name:   "scroll.top",
+		Keymap: keymapNormal,
+		Help:   "Scroll to the first line",
+		Keys:   []string{"g"},
+		Run:    func(u *UI) { u.unfollow().ScrollToBeginning() },
+	},
+	{
+		Name:   "scroll.bottom",
+		Keymap: keymapNormal,
+		Help:   "Scroll to the last line",
+		Keys:   []string{"G", "End"},
+		Run:    func(u *UI) { u.unfollow().ScrollToEnd() },
+	},
+	{
+		Name:   "scroll.left",
+		Keymap: keymapNormal,
+		Help:   "Scroll unwrapped lines left",
+		Keys:   []string{"Left"},
+		Run:    func(u *UI) { u.pager.ScrollLeft(hscrollStep) },
+	},
+	{
+		Name:   "scroll.right",
+		Keymap: keymapNormal,
+		Help:   "Scroll unwrapped lines right",
+		Keys:   []string{"Right"},
+		Run:    func(u *UI) { u.pager.ScrollRight(hscrollStep) },
+	},
+	{
+		Name:   "scroll.home",
+		Keymap: keymapNormal,
+		Help:   "Scroll unwrapped lines back to the first column",
+		Keys:   []string{"Home"},
+		Run:    func(u *UI) { u.pager.ScrollLeft(u.pager.ColumnOffset()) },
+	},
+	{
+		Name:   "wrap.toggle",
+		Keymap: keymapNormal,
+		Help:   "Wrap long lines, or cut them off",
+		Keys:   []string{"z"},
+		Run:    func(u *UI) { u.pager.SetWrap(!u.pager.Wrapping()) },
+	},
+}
+
+var sidebarActions = []Action{
+	{
+		Name:   "sidebar.leave",
+		Keymap: keymapSidebar,
+		Help:   "Move focus back to the panes",
+		Keys:   []string{"Esc"},
+		Run:    func(u *UI) { u.FocusSidebar(false) },
+	},
+	{
+		Name:   "sidebar.next",
+		Keymap: keymapSidebar,
+		Help:   "Select the next row",
+		Keys:   []string{"j", "Down"},
+		Run:    func(u *UI) { u.sidebar.SelectNext() },
+	},
+	{
+		Name:   "sidebar.prev",
+		Keymap: keymapSidebar,
+		Help:   "Select the previous row",
+		Keys:   []string{"k", "Up"},
+		Run:    func(u *UI) { u.sidebar.SelectPrev() },
+	},
+	{
+		Name:   "sidebar.mute",
+		Keymap: keymapSidebar,
+		Help:   "Hide or unhide lines from the selected row",
+		Keys:   []string{"m"},
+		Run: func(u *UI) {
+			if key, ok := u.sidebar.Selected(); ok {
+				u.pager.ToggleMute(key)
+			}
+		},
+	},
+	{
+		Name:   "sidebar.solo",
+		Keymap: keymapSidebar,
+		Help:   "Show only lines from the selected rows",
+		Keys:   []string{"s"},
+		Run: func(u *UI) {
+			if key, ok := u.sidebar.Selected(); ok {
+				u.pager.ToggleSolo(key)
+			}
+		},
+	},
+}
+
+// PagerAppend adds a line to the pager, colouring its prefix by source. While
+// paused, the line is held back until resumed.
+func (u *UI) PagerAppend(line widgets.Line) {
+	if u.paused {
+		u.held = append(u.held, line)
+		u.updateScroll()
+		return
 	}
-	return false
+	u.appendLines([]widgets.Line{line})
 }
 
-func (u *UI) handleScrollEventKeys(ek *tcell.EventKey) bool {
-	switch ek.Key() {
-	case tcell.KeyCtrlD:
-		u.autoscroll = false
-		u.pager.ScrollPageDown(1)
-		return true
-	case tcell.KeyCtrlU:
-		u.autoscroll = false
-		u.pager.ScrollPageUp(1)
-		return true
-	case tcell.KeyDown:
-		u.autoscroll = false
-		u.pager.ScrollDown(1)
-		return true
-	case tcell.KeyUp:
-		u.autoscroll = false
-		u.pager.ScrollUp(1)
-		return true
-	case tcell.KeyRune:
-		switch ek.Rune() {
-		case 'f':
-			u.autoscroll = !u.autoscroll
-			return true
-		case 'j':
-			u.autoscroll = false
-			u.pager.ScrollPageDown(1)
-			return true
-		case 'k':
-			u.autoscroll = false
-			u.pager.ScrollPageUp(1)
-			return true
-		}
+// TogglePause freezes the view, holding back new lines, or resumes it,
+// appending the held lines all at once.
+func (u *UI) TogglePause() {
+	u.paused = !u.paused
+	if u.paused {
+		return
+	}
+
+	held := u.held
+	u.held = nil
+	if len(held) > 0 {
+		u.appendLines(held)
 	}
-	return false
 }
 
-func (u *UI) PagerAppend(line string) {
-	u.pager.Append(line)
-	if u.autoscroll {
-		u.statusbar.SetStatus("FOLLOW", themes.AltTypeNew)
-		u.pager.ScrollToEnd()
-	} else {
-		u.statusbar.SetStatus("NOFOLLOW", themes.AltTypeNormal)
+// CycleThreshold raises the minimum severity of visible lines, wrapping
+// around to showing all lines after the most severe one.
+func (u *UI) CycleThreshold() {
+	next := 0
+	for i, t := range thresholds {
+		if t == u.pager.MinSeverity() {
+			next = (i + 1) % len(thresholds)
+		}
 	}
 
-	pct := u.pager.GetScrollPercentage()
-	u.statusbar.SetScrollPercentage(int(pct * 100))
+	u.pager.SetMinSeverity(thresholds[next])
+	u.updateFilters()
+}
+
+// FocusSidebar moves keyboard focus to or from the sidebar, showing the
+// sidebar if needed.
+func (u *UI) FocusSidebar(focus bool) {
+	if focus && !u.showSidebar {
+		u.ToggleSidebar()
+	}
+	u.focusSidebar = focus
+	u.sidebar.SetFocused(focus)
 }
 
+// PagerLen returns the number of bytes in the scrollback.
 func (u *UI) PagerLen() int {
-	return u.pager.Len()
+	return u.scrollback.Size()
+}
+
+// PopFilter removes the most recently added filter from the pager.
+func (u *UI) PopFilter() {
+	if _, ok := u.pager.PopFilter(); ok {
+		u.updateFilters()
+	}
+}
+
+// Prompt replaces the statusbar with an input prompt. The callback is only
+// invoked if the user submits the prompt.
+func (u *UI) Prompt(prompt string, fn func(string)) {
+	u.input.SetPrompt(prompt, func(text string, ok bool) {
+		u.prompting = false
+		u.RemoveWidget(u.input)
+		u.AddWidget(u.statusbar, 0)
+		if ok {
+			fn(text)
+		}
+	})
+
+	u.prompting = true
+	u.RemoveWidget(u.statusbar)
+	u.AddWidget(u.input, 0)
+}
+
+// PushFilter adds a filter to the pager, which is applied to the existing
+// scrollback as well as any new lines.
+func (u *UI) PushFilter(f widgets.Filter) {
+	u.pager.PushFilter(f)
+	u.updateFilters()
 }
 
 func (u *UI) SetMessage(s string) {
 	u.statusbar.SetMessage(s)
 }
 
+// SetSources updates the sidebar with the state and line rate of every
+// container, keyed by source.
+func (u *UI) SetSources(states map[string]kubelogs.ContainerState, rates map[string]float64) {
+	items := make([]widgets.SidebarItem, 0, len(states))
+	for src, state := range states {
+		items = append(items, widgets.SidebarItem{
+			Source:     src,
+			State:      string(state),
+			StateStyle: u.alts.Select(stateAlts[state]),
+			Rate:       rates[src],
+		})
+	}
+	u.sidebar.SetItems(items)
+}
+
 func (u *UI) SetStatus(s string, a themes.AltType) {
 	u.statusbar.SetStatus(s, a)
 }
+
+// ToggleLegend shows or hides the panel listing the colour of each source.
+func (u *UI) ToggleLegend() {
+	u.showLegend = !u.showLegend
+	if u.showLegend {
+		u.body.AddWidget(u.legend, 0)
+	} else {
+		u.body.RemoveWidget(u.legend)
+	}
+}
+
+// ToggleSidebar shows or hides the sidebar listing every container.
+func (u *UI) ToggleSidebar() {
+	u.showSidebar = !u.showSidebar
+	if u.showSidebar {
+		u.body.InsertWidget(0, u.sidebar, 0)
+	} else {
+		u.FocusSidebar(false)
+		u.body.RemoveWidget(u.sidebar)
+	}
+}
+
+// appendLines adds lines to the scrollback, and then brings every pager up
+// to date.
+func (u *UI) appendLines(lines []widgets.Line) {
+	for _, line := range lines {
+		style, isNew := u.colors.Style(line.Source)
+		if isNew {
+			u.legend.SetEntries(u.colors.Legend())
+		}
+
+		line.PrefixStyle = style
+		line.Style = u.priorities.Select(line.Severity)
+		u.scrollback.Append(line)
+	}
+
+	for _, pn := range u.panes {
+		pn.Pager.Sync()
+	}
+	u.updateScroll()
+}
+
+// searchJump moves to another match of the keyword, if there is one.
+func (u *UI) searchJump(jump func(*widgets.Pager) bool) {
+	if u.pager.Keyword() == "" {
+		u.SetMessage("no keyword to search for")
+		return
+	}
+
+	u.pager.SetFollow(false)
+	if !jump(u.pager) {
+		u.SetMessage(fmt.Sprintf("pattern not found: %s", u.pager.Keyword()))
+	}
+}
+
+// ToggleEvent expands or collapses the continuation lines of the selected
+// line, such as the frames of a stack trace.
+func (u *UI) ToggleEvent() {
+	if !u.unfollow().ToggleExpanded() {
+		u.SetMessage("no continuation lines to expand")
+	}
+}
+
+// unfollow stops the focused pager from following new lines, before it is
+// scrolled, and returns it.
+func (u *UI) unfollow() *widgets.Pager {
+	u.pager.SetFollow(false)
+	return u.pager
+}
+
+func (u *UI) updateFilters() {
+	u.statusbar.SetFilters(filterNames(u.pager))
+	u.updateScroll()
+}
+
+func (u *UI) updateScroll() {
+	if u.paused {
+		u.statusbar.SetPaused(len(u.held))
+	} else if u.pager.Following() {
+		u.statusbar.SetStatus("FOLLOW", themes.AltTypeNew)
+	} else {
+		u.statusbar.SetStatus("NOFOLLOW", themes.AltTypeNormal)
+	}
+
+	pct := u.pager.GetScrollPercentage()
+	u.statusbar.SetScrollPercentage(int(pct * 100))
+	u.statusbar.SetColumn(u.pager.ColumnOffset(), u.pager.Wrapping() && u.pager.Renderer() == nil)
+	u.statusbar.SetClock(u.pager.TopTime())
+	u.updatePaneTitles()
+}
+
+// filterNames describes the severity threshold and filter stack of a pager.
+func filterNames(p *widgets.Pager) []string {
+	fs := make([]string, 0)
+	for _, t := range thresholds {
+		if t != structstream.SeverityUnknown && t == p.MinSeverity() {
+			fs = append(fs, ">="+t.String())
+		}
+	}
+	for _, f := range p.Filters() {
+		fs = append(fs, f.String())
+	}
+	return fs
+}
@@ -0,0 +1,181 @@
+package widgets
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+	"github.com/mattn/go-runewidth"
+)
+
+// bookmarkListMaxWidth caps the width that the bookmark list asks for.
+const bookmarkListMaxWidth = 48
+
+// Bookmark is a line of scrollback that the user named with a letter, to
+// jump back to it later.
+type Bookmark struct {
+	Letter rune
+	// Index is the position of the line in the scrollback, which does not
+	// change as lines are filtered.
+	Index      int
+	Annotation string
+}
+
+// Bookmarks holds the bookmarks into the scrollback, and like the scrollback
+// is shared by all pagers.
+type Bookmarks struct {
+	marks map[rune]*Bookmark
+}
+
+func NewBookmarks() *Bookmarks {
+	return &Bookmarks{
+		marks: make(map[rune]*Bookmark),
+	}
+}
+
+// All returns every bookmark, in scrollback order.
+func (b *Bookmarks) All() []Bookmark {
+	bs := make([]Bookmark, 0, len(b.marks))
+	for _, bm := range b.marks {
+		bs = append(bs, *bm)
+	}
+
+	sort.Slice(bs, func(i, j int) bool {
+		if bs[i].Index != bs[j].Index {
+			return bs[i].Index < bs[j].Index
+		}
+		return bs[i].Letter < bs[j].Letter
+	})
+	return bs
+}
+
+// Annotate sets the annotation of a bookmark, if it exists.
+func (b *Bookmarks) Annotate(letter rune, text string) bool {
+	bm, ok := b.marks[letter]
+	if ok {
+		bm.Annotation = text
+	}
+	return ok
+}
+
+// Get returns the bookmark with a letter.
+func (b *Bookmarks) Get(letter rune) (Bookmark, bool) {
+	bm, ok := b.marks[letter]
+	if !ok {
+		return Bookmark{}, false
+	}
+	return *bm, true
+}
+
+// Set bookmarks the line at a scrollback index with a letter, replacing any
+// bookmark with the same letter along with its annotation.
+func (b *Bookmarks) Set(letter rune, idx int) {
+	b.marks[letter] = &Bookmark{
+		Letter: letter,
+		Index:  idx,
+	}
+}
+
+// BookmarkList is a panel that lists bookmarks, their annotations, and the
+// lines they point to.
+type BookmarkList struct {
+	views.WidgetWatchers
+
+	sb    *Scrollback
+	style tcell.Style
+	title string
+	v     views.View
+
+	bookmarks []Bookmark
+}
+
+func NewBookmarkList(title string, style tcell.Style, sb *Scrollback) *BookmarkList {
+	return &BookmarkList{
+		sb:    sb,
+		style: style,
+		title: title,
+	}
+}
+
+func (bl *BookmarkList) Draw() {
+	if bl.v == nil {
+		return
+	}
+
+	bl.v.Fill(' ', bl.style)
+	_, h := bl.v.Size()
+	for y := 0; y < h; y++ {
+		bl.v.SetContent(0, y, tcell.RuneVLine, nil, bl.style)
+	}
+
+	bl.drawText(2, 0, bl.title, bl.style.Bold(true))
+	y := 1
+	for _, bm := range bl.bookmarks {
+		if y >= h {
+			break
+		}
+
+		bl.drawText(2, y, "'"+string(bm.Letter), bl.style.Bold(true))
+		text := bl.sb.At(bm.Index).String()
+		if bm.Annotation != "" {
+			bl.drawText(5, y, bm.Annotation, bl.style)
+			y++
+			bl.drawText(5, y, text, bl.style.Dim(true))
+		} else {
+			bl.drawText(5, y, text, bl.style)
+		}
+		y++
+	}
+}
+
+func (bl *BookmarkList) HandleEvent(tcell.Event) bool {
+	return false
+}
+
+func (bl *BookmarkList) Resize() {}
+
+// SetBookmarks replaces the bookmarks that are listed.
+func (bl *BookmarkList) SetBookmarks(bs []Bookmark) {
+	bl.bookmarks = bs
+	bl.PostEventWidgetContent(bl)
+}
+
+func (bl *BookmarkList) SetView(v views.View) {
+	bl.v = v
+}
+
+// Size returns the width of the widest row, up to a maximum, and the number
+// of rows, including the title and a second row for each annotation.
+func (bl *BookmarkList) Size() (int, int) {
+	w := runewidth.StringWidth(bl.title) + 3
+	h := 1
+	for _, bm := range bl.bookmarks {
+		for _, s := range []string{bm.Annotation, bl.sb.At(bm.Index).String()} {
+			if sw := runewidth.StringWidth(s) + 6; sw > w {
+				w = sw
+			}
+		}
+		h++
+		if bm.Annotation != "" {
+			h++
+		}
+	}
+
+	if w > bookmarkListMaxWidth {
+		w = bookmarkListMaxWidth
+	}
+	return w, h
+}
+
+// drawText draws a string from column x, cut off at the edge of the panel.
+func (bl *BookmarkList) drawText(x, y int, s string, style tcell.Style) {
+	w, _ := bl.v.Size()
+	for _, c := range s {
+		cw := runewidth.RuneWidth(c)
+		if x+cw > w-1 {
+			break
+		}
+		bl.v.SetContent(x, y, c, nil, style)
+		x += cw
+	}
+}
@@ -0,0 +1,116 @@
+package widgets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Filter decides whether a line is shown in the pager. Filters are stacked,
+// and a line is shown only if every filter in the stack matches it.
+type Filter interface {
+	Match(Line) bool
+	String() string
+}
+
+// RegexpFilter matches lines against a regular expression, or, when it is an
+// exclude filter, matches lines that do not match the expression.
+type RegexpFilter struct {
+	exclude bool
+	expr    string
+	re      *regexp.Regexp
+}
+
+// NewRegexpFilter compiles a filter expression in the style of less(1): a
+// leading '!' turns the filter into an exclude filter.
+func NewRegexpFilter(expr string) (*RegexpFilter, error) {
+	f := &RegexpFilter{expr: expr}
+	if strings.HasPrefix(expr, "!") {
+		f.exclude = true
+		expr = expr[1:]
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	f.re = re
+	return f, nil
+}
+
+func (f *RegexpFilter) Match(l Line) bool {
+	return f.re.MatchString(l.String()) != f.exclude
+}
+
+func (f *RegexpFilter) String() string {
+	return "&" + f.expr
+}
+
+// SourceFilter shows or hides lines by their source, independently of the
+// filter stack. Keys ending in a slash match every source under them, e.g.,
+// "ns/pod/" matches all containers in the pod; other keys match exactly.
+type SourceFilter struct {
+	muted  map[string]bool
+	soloed map[string]bool
+}
+
+func NewSourceFilter() *SourceFilter {
+	return &SourceFilter{
+		muted:  make(map[string]bool),
+		soloed: make(map[string]bool),
+	}
+}
+
+func (f *SourceFilter) Match(l Line) bool {
+	if len(f.soloed) > 0 && !f.matchAny(f.soloed, l.Source) {
+		return false
+	}
+	return !f.matchAny(f.muted, l.Source)
+}
+
+// State returns whether the key is muted or soloed.
+func (f *SourceFilter) State(key string) (bool, bool) {
+	return f.muted[key], f.soloed[key]
+}
+
+func (f *SourceFilter) String() string {
+	return fmt.Sprintf("mute:%d solo:%d", len(f.muted), len(f.soloed))
+}
+
+func (f *SourceFilter) ToggleMute(key string) {
+	toggle(f.muted, key)
+}
+
+func (f *SourceFilter) ToggleSolo(key string) {
+	toggle(f.soloed, key)
+}
+
+func (f *SourceFilter) matchAny(keys map[string]bool, source string) bool {
+	for key := range keys {
+		if key == source || (strings.HasSuffix(key, "/") && strings.HasPrefix(source, key)) {
+			return true
+		}
+	}
+	return false
+}
+
+func toggle(m map[string]bool, key string) {
+	if m[key] {
+		delete(m, key)
+		return
+	}
+	m[key] = true
+}
+
+// Clone returns an independent copy of the source filter.
+func (f *SourceFilter) Clone() *SourceFilter {
+	c := NewSourceFilter()
+	for key := range f.muted {
+		c.muted[key] = true
+	}
+	for key := range f.soloed {
+		c.soloed[key] = true
+	}
+	return c
+}
@@ -0,0 +1,213 @@
+package widgets
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+	"github.com/mattn/go-runewidth"
+)
+
+// helpMaxKeysWidth caps the width of the column of keys.
+const helpMaxKeysWidth = 24
+
+// HelpSection is a titled group of rows in the help overlay.
+type HelpSection struct {
+	Title string
+	Rows  []HelpRow
+}
+
+// HelpRow describes what a set of keys does. Name is how the keys are
+// referred to in configuration.
+type HelpRow struct {
+	Keys string
+	Help string
+	Name string
+}
+
+// Help is a bordered, scrollable list of sections, meant to be drawn over
+// other widgets.
+type Help struct {
+	views.WidgetWatchers
+
+	keyStyle tcell.Style
+	style    tcell.Style
+	title    string
+	v        views.View
+
+	sections []HelpSection
+	top      int
+}
+
+func NewHelp(title string, style, keyStyle tcell.Style) *Help {
+	return &Help{
+		keyStyle: keyStyle,
+		style:    style,
+		title:    title,
+	}
+}
+
+func (hp *Help) Draw() {
+	if hp.v == nil {
+		return
+	}
+
+	hp.v.Fill(' ', hp.style)
+	w, h := hp.v.Size()
+	if w < 4 || h < 3 {
+		return
+	}
+	hp.drawBorder(w, h)
+
+	hp.clamp()
+	kw := hp.keysWidth()
+	y := 1
+	for i, line := range hp.lines() {
+		if i < hp.top {
+			continue
+		}
+		if y >= h-1 {
+			break
+		}
+
+		if line.row == nil {
+			hp.drawText(2, y, w-2, line.title, hp.style.Bold(true).Underline(true))
+		} else {
+			hp.drawText(3, y, w-2, runewidth.Truncate(line.row.Keys, kw, "…"), hp.keyStyle)
+			x := hp.drawText(4+kw, y, w-2, line.row.Help, hp.style)
+
+			name := "(" + line.row.Name + ")"
+			if nx := w - 2 - runewidth.StringWidth(name); nx > x+1 {
+				hp.drawText(nx, y, w-2, name, hp.style.Dim(true))
+			}
+		}
+		y++
+	}
+}
+
+func (hp *Help) HandleEvent(tcell.Event) bool {
+	return false
+}
+
+func (hp *Help) Resize() {}
+
+// ScrollDown scrolls the help down by a number of rows.
+func (hp *Help) ScrollDown(rows int) {
+	hp.top += rows
+	hp.clamp()
+	hp.PostEventWidgetContent(hp)
+}
+
+// ScrollUp scrolls the help up by a number of rows.
+func (hp *Help) ScrollUp(rows int) {
+	hp.top -= rows
+	hp.clamp()
+	hp.PostEventWidgetContent(hp)
+}
+
+// SetSections replaces the contents of the help, scrolling to the top.
+func (hp *Help) SetSections(sections []HelpSection) {
+	hp.sections = sections
+	hp.top = 0
+	hp.PostEventWidgetContent(hp)
+}
+
+func (hp *Help) SetView(v views.View) {
+	hp.v = v
+}
+
+// Size returns the width of the widest row, and the number of rows, each
+// including the border.
+func (hp *Help) Size() (int, int) {
+	kw := hp.keysWidth()
+	w := runewidth.StringWidth(hp.title) + 6
+	lines := hp.lines()
+	for _, line := range lines {
+		lw := runewidth.StringWidth(line.title) + 4
+		if line.row != nil {
+			lw = kw + runewidth.StringWidth(line.row.Help) + runewidth.StringWidth(line.row.Name) + 10
+		}
+		if lw > w {
+			w = lw
+		}
+	}
+	return w, len(lines) + 2
+}
+
+type helpLine struct {
+	title string
+	row   *HelpRow
+}
+
+func (hp *Help) clamp() {
+	h := 0
+	if hp.v != nil {
+		_, h = hp.v.Size()
+	}
+	if max := len(hp.lines()) - (h - 2); hp.top > max {
+		hp.top = max
+	}
+	if hp.top < 0 {
+		hp.top = 0
+	}
+}
+
+func (hp *Help) drawBorder(w, h int) {
+	for x := 1; x < w-1; x++ {
+		hp.v.SetContent(x, 0, tcell.RuneHLine, nil, hp.style)
+		hp.v.SetContent(x, h-1, tcell.RuneHLine, nil, hp.style)
+	}
+	for y := 1; y < h-1; y++ {
+		hp.v.SetContent(0, y, tcell.RuneVLine, nil, hp.style)
+		hp.v.SetContent(w-1, y, tcell.RuneVLine, nil, hp.style)
+	}
+	hp.v.SetContent(0, 0, tcell.RuneULCorner, nil, hp.style)
+	hp.v.SetContent(w-1, 0, tcell.RuneURCorner, nil, hp.style)
+	hp.v.SetContent(0, h-1, tcell.RuneLLCorner, nil, hp.style)
+	hp.v.SetContent(w-1, h-1, tcell.RuneLRCorner, nil, hp.style)
+
+	title := " " + hp.title + " "
+	hp.drawText((w-runewidth.StringWidth(title))/2, 0, w-1, title, hp.style.Bold(true))
+}
+
+func (hp *Help) drawText(x, y, max int, s string, style tcell.Style) int {
+	for _, c := range s {
+		cw := runewidth.RuneWidth(c)
+		if x+cw > max {
+			break
+		}
+		hp.v.SetContent(x, y, c, nil, style)
+		x += cw
+	}
+	return x
+}
+
+func (hp *Help) keysWidth() int {
+	kw := 0
+	for _, s := range hp.sections {
+		for _, row := range s.Rows {
+			if rw := runewidth.StringWidth(row.Keys); rw > kw {
+				kw = rw
+			}
+		}
+	}
+	if kw > helpMaxKeysWidth {
+		kw = helpMaxKeysWidth
+	}
+	return kw
+}
+
+// lines flattens the sections into the rows that are drawn, with a blank
+// line between sections.
+func (hp *Help) lines() []helpLine {
+	lines := make([]helpLine, 0)
+	for i := range hp.sections {
+		s := &hp.sections[i]
+		if i > 0 {
+			lines = append(lines, helpLine{})
+		}
+		lines = append(lines, helpLine{title: s.Title})
+		for j := range s.Rows {
+			lines = append(lines, helpLine{row: &s.Rows[j]})
+		}
+	}
+	return lines
+}
@@ -1,39 +1,33 @@
 package widgets
 
 import (
+	"sort"
 	"strings"
 	"unicode/utf8"
-
-	"github.com/gdamore/tcell/v2"
-	"github.com/gdamore/tcell/v2/views"
-	"github.com/mattn/go-runewidth"
 )
 
-type Highlighter struct {
-	views.WidgetWatchers
+// light is the position of a keyword match, as the row in the pager's
+// visible lines and the rune offset within that row.
+type light struct {
+	row int
+	col int
+}
 
-	lights []int
+type Highlighter struct {
+	lights []light
 	curr   int
 	kw     []rune
-
-	t views.Text
 }
 
 func NewHighlighter() *Highlighter {
 	return &Highlighter{}
 }
 
-func (h *Highlighter) Append(line string) {
-	s := h.t.Text()
-	s += line + "\n"
-	h.t.SetText(s)
-
-	h.reset()
-	h.PostEventWidgetContent(h)
+func (h *Highlighter) Append(row int, line string) {
+	h.lights = append(h.lights, h.find(row, line)...)
 }
 
 func (h *Highlighter) Clear() {
-	h.t.SetText("")
 	h.curr = 0
 	h.kw = nil
 	h.lights = nil
@@ -47,83 +41,66 @@ func (h *Highlighter) Current() int {
 	return h.curr
 }
 
-func (h *Highlighter) Draw() {
-	h.t.Draw()
-}
-
-func (h *Highlighter) HandleEvent(e tcell.Event) bool {
-	return h.t.HandleEvent(e)
-}
-
 func (h *Highlighter) Highlight(idx int) bool {
 	if idx < 0 || idx >= len(h.lights) {
 		return false
 	}
 
 	h.curr = idx
-	h.reset()
-	h.PostEventWidgetContent(h)
 	return true
 }
 
-func (h *Highlighter) Len() int {
-	return len(h.t.Text())
+// Matches returns the rune offsets of keyword matches in the row, and the
+// offset of the current match, or -1 if the current match is elsewhere.
+func (h *Highlighter) Matches(row int) ([]int, int) {
+	curr := -1
+	cols := make([]int, 0)
+
+	i := sort.Search(len(h.lights), func(i int) bool {
+		return h.lights[i].row >= row
+	})
+	for ; i < len(h.lights) && h.lights[i].row == row; i++ {
+		if i == h.curr {
+			curr = h.lights[i].col
+		}
+		cols = append(cols, h.lights[i].col)
+	}
+	return cols, curr
 }
 
 func (h *Highlighter) Pos(idx int) (int, int, bool) {
 	if idx < 0 || idx >= len(h.lights) {
 		return 0, 0, false
 	}
-
-	var x, y int
-	is := h.lights[idx]
-	for i, c := range []rune(h.t.Text()) {
-		if i == is {
-			break
-		}
-		if c == '\n' {
-			x = 0
-			y++
-			continue
-		}
-		x += runewidth.RuneWidth(c)
-	}
-
-	return x, y, true
+	return h.lights[idx].col, h.lights[idx].row, true
 }
 
-func (h *Highlighter) Resize() {
-	h.t.Resize()
+// Reset recomputes all matches against the rows.
+func (h *Highlighter) Reset(rows []string) {
+	h.lights = nil
+	for row, line := range rows {
+		h.Append(row, line)
+	}
 }
 
 func (h *Highlighter) SetKeyword(kw string) {
 	h.kw = []rune(kw)
 	h.curr = -1
-	h.t.SetStyle(h.t.Style())
-	if len(kw) == 0 {
-		return
-	}
-
-	h.reset()
-	h.PostEventWidgetContent(h)
+	h.lights = nil
 }
 
 func (h *Highlighter) Keyword() string {
 	return string(h.kw)
 }
 
-func (h *Highlighter) SetView(v views.View) {
-	h.t.SetView(v)
-}
-
-func (h *Highlighter) Size() (int, int) {
-	return h.t.Size()
+// Width returns the keyword length in runes.
+func (h *Highlighter) Width() int {
+	return len(h.kw)
 }
 
-func (h *Highlighter) reset() {
-	h.lights = nil
+func (h *Highlighter) find(row int, s string) []light {
+	ls := make([]light, 0)
 
-	s := h.t.Text()
 	kw := string(h.kw)
 	for x := 0; len(kw) > 0; {
 		i := strings.Index(s, kw)
@@ -131,21 +108,9 @@ func (h *Highlighter) reset() {
 			break
 		}
 
-		is := x + utf8.RuneCountInString(s[:i])
-		h.lights = append(h.lights, is)
-		s = s[i+len(h.kw):]
-		x += i + len(h.kw)
-	}
-
-	current := h.t.Style().Background(tcell.ColorYellow)
-	reverse := h.t.Style().Reverse(true)
-	for i, is := range h.lights {
-		for j := range h.kw {
-			if i == h.curr {
-				h.t.SetStyleAt(is+j, current)
-			} else {
-				h.t.SetStyleAt(is+j, reverse)
-			}
-		}
+		ls = append(ls, light{row: row, col: x + utf8.RuneCountInString(s[:i])})
+		x += utf8.RuneCountInString(s[:i]) + len(h.kw)
+		s = s[i+len(kw):]
 	}
+	return ls
 }
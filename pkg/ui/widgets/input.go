@@ -1 +1,131 @@
 package widgets
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+	"github.com/mattn/go-runewidth"
+)
+
+// Input is a single-line prompt. The done callback is invoked with the
+// entered text when the user presses Enter, or with ok set to false when the
+// prompt is cancelled.
+type Input struct {
+	views.WidgetWatchers
+
+	style tcell.Style
+	v     views.View
+
+	prompt string
+	text   []rune
+	cursor int
+	done   func(text string, ok bool)
+}
+
+func NewInput(style tcell.Style) *Input {
+	return &Input{
+		style: style,
+	}
+}
+
+func (in *Input) Draw() {
+	if in.v == nil {
+		return
+	}
+
+	in.v.Fill(' ', in.style)
+
+	x := 0
+	for _, c := range in.prompt {
+		in.v.SetContent(x, 0, c, nil, in.style)
+		x += runewidth.RuneWidth(c)
+	}
+	for i, c := range in.text {
+		style := in.style
+		if i == in.cursor {
+			style = style.Reverse(true)
+		}
+		in.v.SetContent(x, 0, c, nil, style)
+		x += runewidth.RuneWidth(c)
+	}
+	if in.cursor == len(in.text) {
+		in.v.SetContent(x, 0, ' ', nil, in.style.Reverse(true))
+	}
+}
+
+func (in *Input) HandleEvent(e tcell.Event) bool {
+	ek, ok := e.(*tcell.EventKey)
+	if !ok {
+		return false
+	}
+
+	switch ek.Key() {
+	case tcell.KeyEnter:
+		in.finish(string(in.text), true)
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		in.finish("", false)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if in.cursor > 0 {
+			in.text = append(in.text[:in.cursor-1], in.text[in.cursor:]...)
+			in.cursor--
+		}
+	case tcell.KeyDelete:
+		if in.cursor < len(in.text) {
+			in.text = append(in.text[:in.cursor], in.text[in.cursor+1:]...)
+		}
+	case tcell.KeyLeft:
+		if in.cursor > 0 {
+			in.cursor--
+		}
+	case tcell.KeyRight:
+		if in.cursor < len(in.text) {
+			in.cursor++
+		}
+	case tcell.KeyHome, tcell.KeyCtrlA:
+		in.cursor = 0
+	case tcell.KeyEnd, tcell.KeyCtrlE:
+		in.cursor = len(in.text)
+	case tcell.KeyCtrlU:
+		in.text = in.text[in.cursor:]
+		in.cursor = 0
+	case tcell.KeyRune:
+		in.text = append(in.text[:in.cursor], append([]rune{ek.Rune()}, in.text[in.cursor:]...)...)
+		in.cursor++
+	default:
+		return false
+	}
+
+	in.PostEventWidgetContent(in)
+	return true
+}
+
+func (in *Input) Resize() {}
+
+// SetPrompt resets the input with a new prompt and completion callback.
+func (in *Input) SetPrompt(prompt string, done func(string, bool)) {
+	in.prompt = prompt
+	in.text = nil
+	in.cursor = 0
+	in.done = done
+	in.PostEventWidgetContent(in)
+}
+
+// SetText replaces the entered text, moving the cursor to its end.
+func (in *Input) SetText(text string) {
+	in.text = []rune(text)
+	in.cursor = len(in.text)
+	in.PostEventWidgetContent(in)
+}
+
+func (in *Input) SetView(v views.View) {
+	in.v = v
+}
+
+func (in *Input) Size() (int, int) {
+	return runewidth.StringWidth(in.prompt) + runewidth.StringWidth(string(in.text)) + 1, 1
+}
+
+func (in *Input) finish(text string, ok bool) {
+	if in.done != nil {
+		in.done(text, ok)
+	}
+}
@@ -0,0 +1,185 @@
+package widgets
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+	"github.com/mattn/go-runewidth"
+)
+
+// InspectorNode is a field shown in the inspector. Nodes with children can be
+// collapsed; Value is what gets copied when the node is selected.
+type InspectorNode struct {
+	Key      string
+	Value    string
+	Children []*InspectorNode
+
+	Collapsed bool
+}
+
+type inspectorRow struct {
+	depth int
+	node  *InspectorNode
+}
+
+// Inspector shows a tree of fields, one per row, with a selected row.
+type Inspector struct {
+	views.WidgetWatchers
+
+	keyStyle tcell.Style
+	style    tcell.Style
+	v        views.View
+
+	nodes    []*InspectorNode
+	rows     []inspectorRow
+	selected int
+	title    string
+	top      int
+}
+
+func NewInspector(style, keyStyle tcell.Style) *Inspector {
+	return &Inspector{
+		keyStyle: keyStyle,
+		style:    style,
+	}
+}
+
+func (in *Inspector) Draw() {
+	if in.v == nil {
+		return
+	}
+
+	in.v.Fill(' ', in.style)
+	w, h := in.v.Size()
+	for y := 0; y < h; y++ {
+		in.v.SetContent(0, y, tcell.RuneVLine, nil, in.style)
+	}
+	in.drawText(2, 0, w, in.title, in.style.Bold(true))
+
+	in.clamp()
+	for y := 1; y < h && in.top+y-1 < len(in.rows); y++ {
+		i := in.top + y - 1
+		row := in.rows[i]
+
+		style, keyStyle := in.style, in.keyStyle
+		if i == in.selected {
+			style, keyStyle = style.Reverse(true), keyStyle.Reverse(true)
+		}
+
+		marker := "  "
+		if len(row.node.Children) > 0 {
+			marker = "▾ "
+			if row.node.Collapsed {
+				marker = "▸ "
+			}
+		}
+
+		x := in.drawText(2+2*row.depth, y, w, marker, style)
+		x = in.drawText(x, y, w, row.node.Key, keyStyle)
+		if len(row.node.Children) == 0 {
+			x = in.drawText(x, y, w, ": ", style)
+			in.drawText(x, y, w, row.node.Value, style)
+		}
+	}
+}
+
+func (in *Inspector) HandleEvent(tcell.Event) bool {
+	return false
+}
+
+func (in *Inspector) Resize() {}
+
+// Selected returns the selected node.
+func (in *Inspector) Selected() (*InspectorNode, bool) {
+	if in.selected < 0 || in.selected >= len(in.rows) {
+		return nil, false
+	}
+	return in.rows[in.selected].node, true
+}
+
+func (in *Inspector) SelectNext() {
+	if in.selected < len(in.rows)-1 {
+		in.selected++
+	}
+	in.PostEventWidgetContent(in)
+}
+
+func (in *Inspector) SelectPrev() {
+	if in.selected > 0 {
+		in.selected--
+	}
+	in.PostEventWidgetContent(in)
+}
+
+// SetNodes replaces the tree shown in the inspector.
+func (in *Inspector) SetNodes(title string, nodes []*InspectorNode) {
+	in.title = title
+	in.nodes = nodes
+	in.selected = 0
+	in.top = 0
+	in.flatten()
+}
+
+func (in *Inspector) SetView(v views.View) {
+	in.v = v
+}
+
+func (in *Inspector) Size() (int, int) {
+	return runewidth.StringWidth(in.title) + 3, len(in.rows) + 1
+}
+
+// Toggle collapses or expands the selected node.
+func (in *Inspector) Toggle() {
+	n, ok := in.Selected()
+	if !ok || len(n.Children) == 0 {
+		return
+	}
+
+	n.Collapsed = !n.Collapsed
+	in.flatten()
+}
+
+func (in *Inspector) clamp() {
+	_, h := in.v.Size()
+	h--
+	if in.selected < in.top {
+		in.top = in.selected
+	}
+	if in.selected >= in.top+h {
+		in.top = in.selected - h + 1
+	}
+	if in.top < 0 {
+		in.top = 0
+	}
+}
+
+func (in *Inspector) drawText(x, y, max int, s string, style tcell.Style) int {
+	for _, c := range s {
+		if x >= max {
+			break
+		}
+		in.v.SetContent(x, y, c, nil, style)
+		x += runewidth.RuneWidth(c)
+	}
+	return x
+}
+
+// flatten recomputes the rows from the expanded parts of the tree.
+func (in *Inspector) flatten() {
+	in.rows = in.rows[:0]
+
+	var walk func(int, []*InspectorNode)
+	walk = func(depth int, nodes []*InspectorNode) {
+		for _, n := range nodes {
+			in.rows = append(in.rows, inspectorRow{depth: depth, node: n})
+			if !n.Collapsed {
+				walk(depth+1, n.Children)
+			}
+		}
+	}
+	walk(0, in.nodes)
+
+	if in.selected >= len(in.rows) {
+		in.selected = len(in.rows) - 1
+	}
+	in.PostEventWidgetContent(in)
+}
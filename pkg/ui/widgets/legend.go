@@ -0,0 +1,87 @@
+package widgets
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+	"github.com/mattn/go-runewidth"
+)
+
+// LegendEntry is a label drawn in its own style.
+type LegendEntry struct {
+	Label string
+	Style tcell.Style
+}
+
+// Legend is a panel that lists labels and the style they are drawn in.
+type Legend struct {
+	views.WidgetWatchers
+
+	style tcell.Style
+	title string
+	v     views.View
+
+	entries []LegendEntry
+}
+
+func NewLegend(title string, style tcell.Style) *Legend {
+	return &Legend{
+		style: style,
+		title: title,
+	}
+}
+
+func (l *Legend) Draw() {
+	if l.v == nil {
+		return
+	}
+
+	l.v.Fill(' ', l.style)
+	_, h := l.v.Size()
+	for y := 0; y < h; y++ {
+		l.v.SetContent(0, y, tcell.RuneVLine, nil, l.style)
+	}
+
+	l.drawText(0, l.title, l.style.Bold(true))
+	for i, e := range l.entries {
+		l.v.SetContent(2, i+1, '■', nil, e.Style)
+		l.drawText(i+1, e.Label, e.Style)
+	}
+}
+
+func (l *Legend) HandleEvent(tcell.Event) bool {
+	return false
+}
+
+func (l *Legend) Resize() {}
+
+func (l *Legend) SetEntries(es []LegendEntry) {
+	l.entries = es
+	l.PostEventWidgetContent(l)
+}
+
+func (l *Legend) SetView(v views.View) {
+	l.v = v
+}
+
+// Size returns the width of the widest label, including the border, swatch
+// and padding, and the number of entries plus the title.
+func (l *Legend) Size() (int, int) {
+	w := runewidth.StringWidth(l.title) + 3
+	for _, e := range l.entries {
+		if ew := runewidth.StringWidth(e.Label) + 5; ew > w {
+			w = ew
+		}
+	}
+	return w, len(l.entries) + 1
+}
+
+func (l *Legend) drawText(y int, s string, style tcell.Style) {
+	x := 2
+	if y > 0 {
+		x = 4
+	}
+	for _, c := range s {
+		l.v.SetContent(x, y, c, nil, style)
+		x += runewidth.RuneWidth(c)
+	}
+}
@@ -0,0 +1,61 @@
+package widgets
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+)
+
+// Locator wraps the screen to find where views are drawn on it, so that mouse
+// events, which are in screen coordinates, can be mapped to the widget under
+// the mouse. It must be the root view of the application.
+type Locator struct {
+	tcell.Screen
+
+	probing bool
+	x, y    int
+}
+
+func NewLocator(s tcell.Screen) *Locator {
+	return &Locator{Screen: s}
+}
+
+// Locate returns the screen position of the top-left cell of a view, which
+// must be nested under the locator. Views do not know their own position, so
+// a cell is drawn through the view and caught before it reaches the screen.
+func (l *Locator) Locate(v views.View) (int, int, bool) {
+	if v == nil {
+		return 0, 0, false
+	}
+	if w, h := v.Size(); w <= 0 || h <= 0 {
+		return 0, 0, false
+	}
+
+	l.probing, l.x, l.y = true, -1, -1
+	v.SetContent(0, 0, ' ', nil, tcell.StyleDefault)
+	l.probing = false
+	return l.x, l.y, l.x >= 0
+}
+
+func (l *Locator) SetContent(x, y int, ch rune, comb []rune, style tcell.Style) {
+	if l.probing {
+		l.x, l.y = x, y
+		return
+	}
+	l.Screen.SetContent(x, y, ch, comb, style)
+}
+
+// Within converts a screen position to a position in a view, if it falls
+// within the view.
+func (l *Locator) Within(v views.View, x, y int) (int, int, bool) {
+	vx, vy, ok := l.Locate(v)
+	if !ok {
+		return 0, 0, false
+	}
+
+	w, h := v.Size()
+	x, y = x-vx, y-vy
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return 0, 0, false
+	}
+	return x, y, true
+}
@@ -1,54 +1,206 @@
 package widgets
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/gdamore/tcell/v2/views"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/ripta/axe/pkg/structstream"
 )
 
+// Renderer formats lines for display in place of their text, e.g., as the
+// columns of a table, under a header row.
+type Renderer interface {
+	// Header returns the header row, and the rune offsets of the start and
+	// end of the part of the header to highlight.
+	Header() (string, int, int)
+	// Render returns one row of text per line.
+	Render([]Line) []string
+}
+
+// Line is a single line of scrollback in the pager.
+type Line struct {
+	// Index is the position of the line in the structured buffer.
+	Index int
+	// Source identifies where the line came from, e.g., ns/pod/container.
+	Source string
+
+	Prefix      string
+	PrefixStyle tcell.Style
+	Text        string
+	Style       tcell.Style
+
+	// Severity is the parsed severity of the line, if any.
+	Severity structstream.Severity
+	// Time is when the line was logged, according to the API server or the
+	// line itself, or else when it was received.
+	Time time.Time
+
+	// More holds the continuation lines of a multi-line event, such as a
+	// stack trace, which are collapsed under the line unless expanded.
+	More []string
+}
+
+// String returns the line as displayed, including its prefix.
+func (l Line) String() string {
+	return l.Prefix + l.Text
+}
+
+// FullText returns the text of the line and its continuation lines, joined
+// by newlines.
+func (l Line) FullText() string {
+	if len(l.More) == 0 {
+		return l.Text
+	}
+	return l.Text + "\n" + strings.Join(l.More, "\n")
+}
+
 type Pager struct {
 	views.WidgetWatchers
 
-	app *views.Application
-	h   *Highlighter
-	v   views.View
-	vp  views.ViewPort
+	app   *views.Application
+	h     *Highlighter
+	v     views.View
+	style tcell.Style
+
+	// matchStyle and currentStyle are the styles of keyword matches, and
+	// of the current match.
+	matchStyle   tcell.Style
+	currentStyle tcell.Style
+
+	// visible holds the indices of the scrollback lines that pass every
+	// filter, in order, up to the last synced line.
+	sb      *Scrollback
+	seen    int
+	filters []Filter
+	sources *SourceFilter
+	visible []int
+
+	// minSeverity hides lines less severe than it; the unknown severity
+	// shows every line, including those without a severity.
+	minSeverity structstream.Severity
+
+	// renderer, if set, formats lines in place of their text.
+	renderer Renderer
+
+	// wrap breaks lines that are wider than the screen over several screen
+	// lines; otherwise, lines are cut off, and left is the number of screen
+	// columns scrolled past on the left. Rendered lines are never wrapped.
+	wrap bool
+	left int
+
+	// cursor is the scrollback index of the selected line, or -1 if there is
+	// no selected line.
+	cursor int
+	follow bool
+	top    int
+
+	// expanded holds the scrollback indices of the multi-line events whose
+	// continuation lines are shown.
+	expanded map[int]bool
+
+	// marked holds the scrollback indices of the first and last lines of a
+	// range of lines being marked with the mouse, or -1 if there is none.
+	marked [2]int
 }
 
-func NewPager(app *views.Application) *Pager {
+func NewPager(app *views.Application, sb *Scrollback) *Pager {
 	p := &Pager{
-		app: app,
-		h:   NewHighlighter(),
+		app:          app,
+		h:            NewHighlighter(),
+		sb:           sb,
+		sources:      NewSourceFilter(),
+		style:        tcell.StyleDefault,
+		matchStyle:   tcell.StyleDefault.Reverse(true),
+		currentStyle: tcell.StyleDefault.Background(tcell.ColorYellow),
+		cursor:       -1,
+		follow:       true,
+		expanded:     make(map[int]bool),
+		marked:       [2]int{-1, -1},
 	}
-	p.h.SetView(&p.vp)
+	p.Sync()
 	return p
 }
 
-func (p *Pager) Append(line string) {
-	p.h.Append(line)
-	w, h := p.h.Size()
-	p.vp.SetContentSize(w, h, true)
-	p.vp.ValidateView()
+// Cursor returns the selected line.
+func (p *Pager) Cursor() (Line, bool) {
+	if p.cursor < 0 {
+		return Line{}, false
+	}
+	return p.sb.At(p.cursor), true
 }
 
-func (p *Pager) Clear() {
-	p.h.Clear()
-	w, h := p.h.Size()
-	p.vp.SetContentSize(w, h, true)
-	p.vp.ValidateView()
+// Current returns the scrollback index of the selected line or, if there is
+// none, of the last line on screen.
+func (p *Pager) Current() (int, bool) {
+	if p.cursor >= 0 {
+		return p.cursor, true
+	}
+	if len(p.visible) == 0 {
+		return 0, false
+	}
+	return p.visible[p.bottom()], true
 }
 
 func (p *Pager) Draw() {
 	if p.v == nil {
 		return
 	}
-	p.h.Draw()
+
+	p.v.Fill(' ', p.style)
+
+	lines := p.onScreen()
+	if p.renderer == nil {
+		y := 0
+		for i, line := range lines {
+			for j, text := range p.display(p.top + i) {
+				if j == 0 {
+					y += p.drawRow(y, p.top+i, text, len([]rune(line.Prefix)), true)
+				} else {
+					y += p.drawRow(y, p.top+i, text, 0, false)
+				}
+			}
+		}
+		return
+	}
+
+	header, hs, he := p.renderer.Header()
+	p.drawHeader(header, hs, he)
+	for i, text := range p.renderer.Render(lines) {
+		p.drawRow(i+1, p.top+i, text, 0, false)
+	}
+}
+
+// Filters returns the filter stack, from oldest to newest.
+func (p *Pager) Filters() []Filter {
+	return p.filters
+}
+
+// Following returns whether the pager scrolls to the end on new lines.
+func (p *Pager) Following() bool {
+	return p.follow
+}
+
+// ColumnOffset returns the number of screen columns scrolled past on the
+// left, which is always zero when wrapping.
+func (p *Pager) ColumnOffset() int {
+	if p.wrapping() {
+		return 0
+	}
+	return p.left
 }
 
 func (p *Pager) GetScrollPercentage() float64 {
-	_, vy, _, _ := p.vp.GetVisible()
-	_, cy := p.vp.GetContentSize()
-	_, vh := p.vp.Size()
-	return float64(vy) / float64(cy-vh)
+	max := p.maxTop()
+	if max <= 0 {
+		return 1
+	}
+	return float64(p.top) / float64(max)
 }
 
 func (p *Pager) HandleEvent(e tcell.Event) bool {
@@ -79,56 +231,346 @@ func (p *Pager) Highlight(idx int) bool {
 		return false
 	}
 
-	x, y, ok := p.h.Pos(idx)
+	_, y, ok := p.h.Pos(idx)
 	if !ok {
 		return false
 	}
 
-	p.vp.Center(x, y)
+	h := p.height()
+	p.top = y - h/2
+	p.clamp()
+	p.PostEventWidgetContent(p)
+	return true
+}
+
+// LineAt returns the scrollback index of the line drawn on screen line y.
+func (p *Pager) LineAt(y int) (int, bool) {
+	if p.renderer != nil {
+		y--
+	}
+	if y < 0 {
+		return 0, false
+	}
+
+	for row := p.top; row < len(p.visible); row++ {
+		if y -= p.lineHeight(row); y < 0 {
+			return p.visible[row], true
+		}
+	}
+	return 0, false
+}
+
+// Mark marks the range of lines between two scrollback indices, inclusive,
+// in either order.
+func (p *Pager) Mark(from, to int) {
+	if from > to {
+		from, to = to, from
+	}
+	p.marked = [2]int{from, to}
+	p.PostEventWidgetContent(p)
+}
+
+// Marked returns the visible lines in the marked range.
+func (p *Pager) Marked() []Line {
+	lines := make([]Line, 0)
+	for _, i := range p.visible {
+		if p.isMarked(i) {
+			lines = append(lines, p.sb.At(i))
+		}
+	}
+	return lines
+}
+
+// Unmark clears the marked range.
+func (p *Pager) Unmark() {
+	p.marked = [2]int{-1, -1}
+	p.PostEventWidgetContent(p)
+}
+
+// MoveCursor moves the selected line by delta visible lines, scrolling to
+// keep it on screen. Without a selected line, the last line on screen is
+// selected instead.
+func (p *Pager) MoveCursor(delta int) {
+	if len(p.visible) == 0 {
+		return
+	}
+
+	row, ok := p.cursorRow()
+	if p.cursor < 0 {
+		row, delta = p.bottom(), 0
+	} else if !ok && delta > 0 {
+		// The selected line was filtered out; row is where it would have been.
+		delta--
+	}
+
+	row += delta
+	if row >= len(p.visible) {
+		row = len(p.visible) - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+
+	p.cursor = p.visible[row]
+	p.scrollTo(row)
+	p.PostEventWidgetContent(p)
+}
+
+// SeekTime scrolls the first line at or after a time to the top, and selects
+// it. Lines are assumed to be in time order, which lines from different
+// sources only roughly are. Without such a line, the last line is selected.
+func (p *Pager) SeekTime(t time.Time) bool {
+	if len(p.visible) == 0 {
+		return false
+	}
+
+	row := sort.Search(len(p.visible), func(i int) bool {
+		return !p.sb.At(p.visible[i]).Time.Before(t)
+	})
+	if row == len(p.visible) {
+		row--
+	}
+
+	p.cursor = p.visible[row]
+	p.top = row
+	p.clamp()
+	p.PostEventWidgetContent(p)
+	return true
+}
+
+// TopTime returns the time of the line at the top of the screen.
+func (p *Pager) TopTime() (time.Time, bool) {
+	if p.top >= len(p.visible) {
+		return time.Time{}, false
+	}
+	return p.sb.At(p.visible[p.top]).Time, true
+}
+
+// SetCursor selects the line at a scrollback index, and returns whether it is
+// visible; lines hidden by filters cannot be selected.
+func (p *Pager) SetCursor(idx int) bool {
+	row := sort.SearchInts(p.visible, idx)
+	if row >= len(p.visible) || p.visible[row] != idx {
+		return false
+	}
+
+	p.cursor = idx
+	p.scrollTo(row)
 	p.PostEventWidgetContent(p)
 	return true
 }
 
-func (p *Pager) Len() int {
-	return p.h.Len()
+// ToggleExpanded shows or hides the continuation lines of the selected line,
+// and returns whether it has any. Without a selected line, the last line on
+// screen is selected first.
+func (p *Pager) ToggleExpanded() bool {
+	if p.cursor < 0 {
+		p.MoveCursor(0)
+	}
+	if p.cursor < 0 || len(p.sb.At(p.cursor).More) == 0 {
+		return false
+	}
+
+	if p.expanded[p.cursor] {
+		delete(p.expanded, p.cursor)
+	} else {
+		p.expanded[p.cursor] = true
+	}
+	p.clamp()
+	if row, ok := p.cursorRow(); ok {
+		p.scrollTo(row)
+	}
+	p.PostEventWidgetContent(p)
+	return true
+}
+
+// PopFilter removes the newest filter from the stack and recomputes the
+// visible lines.
+func (p *Pager) PopFilter() (Filter, bool) {
+	if len(p.filters) == 0 {
+		return nil, false
+	}
+
+	f := p.filters[len(p.filters)-1]
+	p.filters = p.filters[:len(p.filters)-1]
+	p.refilter()
+	return f, true
+}
+
+// Refilter recomputes the visible lines, after lines in the scrollback were
+// replaced.
+func (p *Pager) Refilter() {
+	p.refilter()
+}
+
+// PushFilter adds a filter to the stack and recomputes the visible lines.
+func (p *Pager) PushFilter(f Filter) {
+	p.filters = append(p.filters, f)
+	p.refilter()
+}
+
+// MinSeverity returns the minimum severity of visible lines.
+func (p *Pager) MinSeverity() structstream.Severity {
+	return p.minSeverity
+}
+
+// SetMinSeverity hides lines less severe than a severity, and recomputes the
+// visible lines.
+func (p *Pager) SetMinSeverity(sev structstream.Severity) {
+	p.minSeverity = sev
+	p.refilter()
+}
+
+// SourceState returns whether a source key is muted or soloed.
+func (p *Pager) SourceState(key string) (bool, bool) {
+	return p.sources.State(key)
+}
+
+// ToggleMute hides or unhides lines from a source key, and recomputes the
+// visible lines.
+func (p *Pager) ToggleMute(key string) {
+	p.sources.ToggleMute(key)
+	p.refilter()
+}
+
+// ToggleSolo adds or removes a source key from the set of sources that are
+// exclusively shown, and recomputes the visible lines.
+func (p *Pager) ToggleSolo(key string) {
+	p.sources.ToggleSolo(key)
+	p.refilter()
 }
 
 func (p *Pager) Resize() {
-	w, h := p.v.Size()
-	p.vp.Resize(0, 0, w, h)
-	p.vp.ValidateView()
+	p.clamp()
+	if p.follow {
+		p.ScrollToEnd()
+	}
+}
+
+// ScrollLeft scrolls unwrapped lines to the left by a number of columns.
+func (p *Pager) ScrollLeft(cols int) {
+	if p.wrapping() {
+		return
+	}
+	p.left -= cols
+	if p.left < 0 {
+		p.left = 0
+	}
+	p.PostEventWidgetContent(p)
+}
+
+// ScrollRight scrolls unwrapped lines to the right by a number of columns, up
+// to the end of the widest line on screen.
+func (p *Pager) ScrollRight(cols int) {
+	if p.wrapping() || p.v == nil {
+		return
+	}
+	p.left += cols
+
+	w, _ := p.v.Size()
+	if max := p.widest() - w; p.left > max {
+		p.left = max
+	}
+	if p.left < 0 {
+		p.left = 0
+	}
+	p.PostEventWidgetContent(p)
 }
 
 func (p *Pager) ScrollDown(rows int) {
-	p.vp.ScrollDown(rows)
+	p.top += rows
+	p.clamp()
 }
 
 func (p *Pager) ScrollPageDown(pg int) {
-	_, h := p.v.Size()
-	p.vp.ScrollDown(h * pg / 2)
+	h := p.height()
+	p.ScrollDown(h * pg / 2)
 }
 
 func (p *Pager) ScrollPageUp(pg int) {
-	_, h := p.v.Size()
-	p.vp.ScrollUp(h * pg / 2)
+	h := p.height()
+	p.ScrollUp(h * pg / 2)
 }
 
 func (p *Pager) ScrollToBeginning() {
-	_, h := p.v.Size()
-	p.vp.ScrollUp(h)
+	p.top = 0
 }
 
 func (p *Pager) ScrollToEnd() {
-	_, h := p.v.Size()
-	p.vp.ScrollDown(h)
+	p.top = len(p.visible)
+	p.clamp()
 }
 
 func (p *Pager) ScrollUp(rows int) {
-	p.vp.ScrollUp(rows)
+	p.top -= rows
+	p.clamp()
+}
+
+// SetRenderer sets or, if nil, unsets the renderer of lines.
+func (p *Pager) SetRenderer(r Renderer) {
+	p.renderer = r
+	p.clamp()
+	if p.follow {
+		p.ScrollToEnd()
+	}
+	p.PostEventWidgetContent(p)
+}
+
+// Renderer returns the renderer of lines, if any.
+func (p *Pager) Renderer() Renderer {
+	return p.renderer
+}
+
+// SetWrap sets whether lines wider than the screen are wrapped.
+func (p *Pager) SetWrap(wrap bool) {
+	p.wrap = wrap
+	p.clamp()
+	if p.follow {
+		p.ScrollToEnd()
+	}
+	if row, ok := p.cursorRow(); ok {
+		p.scrollTo(row)
+	}
+	p.PostEventWidgetContent(p)
+}
+
+// Within converts a screen position to a position in the pager, if it falls
+// within the pager.
+func (p *Pager) Within(l *Locator, x, y int) (int, int, bool) {
+	return l.Within(p.v, x, y)
+}
+
+// Wrapping returns whether lines wider than the screen are wrapped.
+func (p *Pager) Wrapping() bool {
+	return p.wrap
+}
+
+// SetStyle sets the style of the pager where there are no lines.
+func (p *Pager) SetStyle(style tcell.Style) {
+	p.style = style
+	p.PostEventWidgetContent(p)
+}
+
+// SetMatchStyles sets the styles of keyword matches, and of the current
+// match.
+func (p *Pager) SetMatchStyles(match, current tcell.Style) {
+	p.matchStyle = match
+	p.currentStyle = current
+	p.PostEventWidgetContent(p)
+}
+
+// SetFollow sets whether the pager scrolls to the end on new lines.
+func (p *Pager) SetFollow(follow bool) {
+	p.follow = follow
+	if follow {
+		p.cursor = -1
+		p.ScrollToEnd()
+	}
 }
 
 func (p *Pager) SetKeyword(kw string) {
 	p.h.SetKeyword(kw)
+	p.h.Reset(p.rows())
 	p.PostEventWidgetContent(p)
 }
 
@@ -138,7 +580,6 @@ func (p *Pager) Keyword() string {
 
 func (p *Pager) SetView(v views.View) {
 	p.v = v
-	p.vp.SetView(v)
 	if v == nil {
 		return
 	}
@@ -155,3 +596,341 @@ func (p *Pager) Size() (int, int) {
 	}
 	return w, h
 }
+
+// Split returns a new pager over the same scrollback, with a copy of this
+// pager's filters and scroll position.
+func (p *Pager) Split() *Pager {
+	np := &Pager{
+		app:          p.app,
+		h:            NewHighlighter(),
+		sb:           p.sb,
+		filters:      append([]Filter(nil), p.filters...),
+		sources:      p.sources.Clone(),
+		minSeverity:  p.minSeverity,
+		style:        p.style,
+		matchStyle:   p.matchStyle,
+		currentStyle: p.currentStyle,
+		renderer:     p.renderer,
+		wrap:         p.wrap,
+		left:         p.left,
+		cursor:       -1,
+		follow:       p.follow,
+		top:          p.top,
+		expanded:     make(map[int]bool, len(p.expanded)),
+		marked:       [2]int{-1, -1},
+	}
+	for idx := range p.expanded {
+		np.expanded[idx] = true
+	}
+	np.Sync()
+	return np
+}
+
+// Sync filters the lines appended to the scrollback since the last sync.
+func (p *Pager) Sync() {
+	for ; p.seen < p.sb.Len(); p.seen++ {
+		line := p.sb.At(p.seen)
+		if p.match(line) {
+			p.h.Append(len(p.visible), line.String())
+			p.visible = append(p.visible, p.seen)
+		}
+	}
+
+	if p.follow {
+		p.ScrollToEnd()
+	}
+}
+
+// bottom returns the last row that fits entirely on screen, or the top row if
+// even it does not fit.
+func (p *Pager) bottom() int {
+	h := p.height()
+	row, used := p.top, 0
+	for ; row < len(p.visible); row++ {
+		if used += p.lineHeight(row); used > h {
+			break
+		}
+	}
+	if row > p.top {
+		row--
+	}
+	return row
+}
+
+func (p *Pager) clamp() {
+	if max := p.maxTop(); p.top > max {
+		p.top = max
+	}
+	if p.top < 0 {
+		p.top = 0
+	}
+}
+
+// cursorRow returns the position of the selected line among the visible
+// lines, if it is visible.
+func (p *Pager) cursorRow() (int, bool) {
+	if p.cursor < 0 {
+		return 0, false
+	}
+
+	row := sort.SearchInts(p.visible, p.cursor)
+	if row >= len(p.visible) || p.visible[row] != p.cursor {
+		return row, false
+	}
+	return row, true
+}
+
+// display returns the screen text of a visible row: the line as displayed,
+// followed by its continuation lines if it is expanded, or by a count of them
+// if it is collapsed. Continuation lines are indented past the prefix.
+func (p *Pager) display(row int) []string {
+	idx := p.visible[row]
+	line := p.sb.At(idx)
+	if len(line.More) == 0 {
+		return []string{line.String()}
+	}
+	if !p.expanded[idx] {
+		return []string{fmt.Sprintf("%s [+%d lines]", line.String(), len(line.More))}
+	}
+
+	indent := strings.Repeat(" ", runewidth.StringWidth(line.Prefix))
+	texts := make([]string, 0, len(line.More)+1)
+	texts = append(texts, line.String())
+	for _, more := range line.More {
+		// Tabs have no width on screen, but indent most stack frames.
+		texts = append(texts, indent+strings.ReplaceAll(more, "\t", "    "))
+	}
+	return texts
+}
+
+func (p *Pager) drawHeader(header string, hs, he int) {
+	w, _ := p.v.Size()
+	style := p.style.Bold(true).Underline(true)
+
+	x := -p.left
+	for i, c := range []rune(header) {
+		if x >= w {
+			break
+		}
+
+		cw := runewidth.RuneWidth(c)
+		if x >= 0 {
+			if i >= hs && i < he {
+				p.v.SetContent(x, 0, c, nil, style.Reverse(true))
+			} else {
+				p.v.SetContent(x, 0, c, nil, style)
+			}
+		}
+		x += cw
+	}
+}
+
+// drawRow draws text for a visible row starting at screen line y, and returns
+// the number of screen lines it takes up. The first plen runes are drawn in
+// the line's prefix style, and keyword matches are only highlighted if the
+// text is the line as displayed.
+func (p *Pager) drawRow(y, row int, text string, plen int, highlight bool) int {
+	w, h := p.v.Size()
+	cols, curr := p.h.Matches(row)
+	kw := p.h.Width()
+	if !highlight {
+		cols = nil
+	}
+
+	line := p.sb.At(p.visible[row])
+
+	wrap := p.wrapping()
+	x, dy := -p.left, 0
+	if wrap {
+		x = 0
+	}
+
+	for i, c := range []rune(text) {
+		// A wide rune that does not fit at the end of a screen line is moved
+		// to the start of the next one, rather than being split.
+		cw := runewidth.RuneWidth(c)
+		if wrap && x > 0 && x+cw > w {
+			x, dy = 0, dy+1
+		}
+		if x >= w || y+dy >= h {
+			break
+		}
+
+		style := line.Style
+		if i < plen {
+			style = line.PrefixStyle
+		}
+		if p.visible[row] == p.cursor || p.isMarked(p.visible[row]) {
+			style = style.Reverse(true)
+		}
+		for _, col := range cols {
+			if i >= col && i < col+kw {
+				style = p.matchStyle
+				if col == curr {
+					style = p.currentStyle
+				}
+			}
+		}
+
+		if x >= 0 {
+			p.v.SetContent(x, y+dy, c, nil, style)
+		}
+		x += cw
+	}
+	return dy + 1
+}
+
+// height returns the number of screen lines available for visible lines.
+func (p *Pager) height() int {
+	if p.v == nil {
+		return 0
+	}
+
+	_, h := p.v.Size()
+	if p.renderer != nil && h > 0 {
+		h--
+	}
+	return h
+}
+
+func (p *Pager) isMarked(idx int) bool {
+	return p.marked[0] >= 0 && idx >= p.marked[0] && idx <= p.marked[1]
+}
+
+// lineHeight returns the number of screen lines taken up by a visible row.
+func (p *Pager) lineHeight(row int) int {
+	if p.renderer != nil {
+		return 1
+	}
+	texts := p.display(row)
+	if !p.wrapping() {
+		return len(texts)
+	}
+
+	w, _ := p.v.Size()
+	n := 0
+	for _, text := range texts {
+		x := 0
+		n++
+		for _, c := range text {
+			cw := runewidth.RuneWidth(c)
+			if x > 0 && x+cw > w {
+				n, x = n+1, 0
+			}
+			x += cw
+		}
+	}
+	return n
+}
+
+func (p *Pager) match(line Line) bool {
+	if p.minSeverity != structstream.SeverityUnknown && line.Severity < p.minSeverity {
+		return false
+	}
+	if !p.sources.Match(line) {
+		return false
+	}
+	for _, f := range p.filters {
+		if !f.Match(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// maxTop returns the top row when scrolled to the end.
+func (p *Pager) maxTop() int {
+	if len(p.visible) == 0 {
+		return 0
+	}
+	return p.topFor(len(p.visible) - 1)
+}
+
+func (p *Pager) refilter() {
+	p.visible = p.visible[:0]
+	for i := 0; i < p.seen; i++ {
+		if p.match(p.sb.At(i)) {
+			p.visible = append(p.visible, i)
+		}
+	}
+
+	p.h.Reset(p.rows())
+	if p.follow {
+		p.ScrollToEnd()
+	}
+	p.clamp()
+	p.PostEventWidgetContent(p)
+}
+
+// onScreen returns the lines from the top row that may be on screen. Every
+// line takes up at least one screen line, so there are never more lines on
+// screen than its height.
+func (p *Pager) onScreen() []Line {
+	lines := make([]Line, 0)
+	for row := p.top; row < p.top+p.height() && row < len(p.visible); row++ {
+		lines = append(lines, p.sb.At(p.visible[row]))
+	}
+	return lines
+}
+
+func (p *Pager) rows() []string {
+	rows := make([]string, 0, len(p.visible))
+	for _, i := range p.visible {
+		rows = append(rows, p.sb.At(i).String())
+	}
+	return rows
+}
+
+// scrollTo scrolls as little as possible to bring a row entirely on screen.
+func (p *Pager) scrollTo(row int) {
+	if row < p.top {
+		p.top = row
+	}
+	if row > p.bottom() {
+		p.top = p.topFor(row)
+	}
+	p.clamp()
+}
+
+// topFor returns the top row that puts a row on the last screen line, with as
+// many rows above it as fit entirely on screen.
+func (p *Pager) topFor(row int) int {
+	h := p.height()
+	top, used := row, p.lineHeight(row)
+	for top > 0 {
+		lh := p.lineHeight(top - 1)
+		if used+lh > h {
+			break
+		}
+		top, used = top-1, used+lh
+	}
+	return top
+}
+
+// widest returns the screen width of the widest text on screen, including
+// the header of the renderer, if any.
+func (p *Pager) widest() int {
+	var texts []string
+	if p.renderer == nil {
+		for i := range p.onScreen() {
+			texts = append(texts, p.display(p.top+i)...)
+		}
+	} else {
+		header, _, _ := p.renderer.Header()
+		texts = append(p.renderer.Render(p.onScreen()), header)
+	}
+
+	max := 0
+	for _, text := range texts {
+		if tw := runewidth.StringWidth(text); tw > max {
+			max = tw
+		}
+	}
+	return max
+}
+
+// wrapping returns whether lines are being wrapped, which they never are when
+// a renderer is set.
+func (p *Pager) wrapping() bool {
+	return p.wrap && p.renderer == nil && p.v != nil
+}
@@ -0,0 +1,47 @@
+package widgets
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+)
+
+// Pane is a pager with an optional title line, which is shown when the view
+// is split into several panes.
+type Pane struct {
+	views.BoxLayout
+
+	Pager *Pager
+
+	showTitle bool
+	title     *views.Text
+}
+
+func NewPane(p *Pager) *Pane {
+	pn := &Pane{
+		Pager: p,
+		title: views.NewText(),
+	}
+
+	pn.SetOrientation(views.Vertical)
+	pn.AddWidget(p, 1)
+	return pn
+}
+
+func (pn *Pane) SetTitle(s string, style tcell.Style) {
+	pn.title.SetText(s)
+	pn.title.SetStyle(style)
+}
+
+// ShowTitle shows or hides the title line.
+func (pn *Pane) ShowTitle(show bool) {
+	if show == pn.showTitle {
+		return
+	}
+
+	pn.showTitle = show
+	if show {
+		pn.InsertWidget(0, pn.title, 0)
+	} else {
+		pn.RemoveWidget(pn.title)
+	}
+}
@@ -0,0 +1,40 @@
+package widgets
+
+// Scrollback holds every line received, and is shared by all pagers. Each
+// pager keeps its own view of the lines that pass its filters.
+type Scrollback struct {
+	lines []Line
+	size  int
+}
+
+func NewScrollback() *Scrollback {
+	return &Scrollback{}
+}
+
+func (s *Scrollback) Append(line Line) {
+	s.lines = append(s.lines, line)
+	s.size += len(line.Prefix) + len(line.Text) + 1
+	for _, more := range line.More {
+		s.size += len(more) + 1
+	}
+}
+
+// Set replaces the line at an index with the same line, parsed again. Pagers
+// must be refiltered, as the line may no longer pass their filters.
+func (s *Scrollback) Set(i int, line Line) {
+	s.lines[i] = line
+}
+
+func (s *Scrollback) At(i int) Line {
+	return s.lines[i]
+}
+
+// Len returns the number of lines.
+func (s *Scrollback) Len() int {
+	return len(s.lines)
+}
+
+// Size returns the number of bytes in all lines.
+func (s *Scrollback) Size() int {
+	return s.size
+}
@@ -0,0 +1,244 @@
+package widgets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/views"
+	"github.com/mattn/go-runewidth"
+)
+
+// sidebarMaxWidth caps the width that the sidebar asks for.
+const sidebarMaxWidth = 48
+
+// SidebarItem describes a single log stream.
+type SidebarItem struct {
+	// Source is the namespace, pod, and container name, separated by slashes.
+	Source string
+	State  string
+	// StateStyle is the style used to draw the state.
+	StateStyle tcell.Style
+	// Rate is in lines per second.
+	Rate float64
+}
+
+type sidebarRow struct {
+	key   string
+	label string
+	depth int
+	item  *SidebarItem
+}
+
+// Sidebar lists log streams as a tree of namespaces, pods, and containers.
+// Rows can be selected, and each row is marked if its key is muted or soloed.
+type Sidebar struct {
+	views.WidgetWatchers
+
+	style tcell.Style
+	v     views.View
+
+	focused  bool
+	rows     []sidebarRow
+	selected int
+	state    func(key string) (bool, bool)
+	top      int
+}
+
+func NewSidebar(style tcell.Style) *Sidebar {
+	return &Sidebar{
+		style: style,
+		state: func(string) (bool, bool) { return false, false },
+	}
+}
+
+func (sb *Sidebar) Draw() {
+	if sb.v == nil {
+		return
+	}
+
+	sb.v.Fill(' ', sb.style)
+	w, h := sb.v.Size()
+	for y := 0; y < h; y++ {
+		sb.v.SetContent(w-1, y, tcell.RuneVLine, nil, sb.style)
+	}
+
+	sb.clamp()
+	for y := 0; y < h && sb.top+y < len(sb.rows); y++ {
+		i := sb.top + y
+		row := sb.rows[i]
+
+		style := sb.style
+		if i == sb.selected {
+			style = style.Reverse(sb.focused).Bold(true)
+		}
+
+		marker := " "
+		muted, soloed := sb.state(row.key)
+		if soloed {
+			marker = "*"
+		} else if muted {
+			marker = "-"
+			style = style.Dim(true)
+		}
+
+		x := sb.drawText(0, y, w-1, marker+strings.Repeat("  ", row.depth)+row.label, style)
+		if row.item == nil {
+			continue
+		}
+
+		rate := fmt.Sprintf(" %.0f/s", row.item.Rate)
+		rx := w - 1 - runewidth.StringWidth(rate)
+		sx := rx - runewidth.StringWidth(row.item.State) - 1
+		if sx > x {
+			sb.drawText(sx, y, rx, row.item.State, row.item.StateStyle)
+		}
+		if rx > x {
+			sb.drawText(rx, y, w-1, rate, style)
+		}
+	}
+}
+
+func (sb *Sidebar) HandleEvent(tcell.Event) bool {
+	return false
+}
+
+func (sb *Sidebar) Resize() {}
+
+// Selected returns the key of the selected row. Namespace and pod keys end
+// with a slash, so that they can be used as prefixes of source keys.
+func (sb *Sidebar) Selected() (string, bool) {
+	if sb.selected < 0 || sb.selected >= len(sb.rows) {
+		return "", false
+	}
+	return sb.rows[sb.selected].key, true
+}
+
+// SelectAt selects the row drawn on screen line y, if any.
+func (sb *Sidebar) SelectAt(y int) bool {
+	i := sb.top + y
+	if y < 0 || i >= len(sb.rows) {
+		return false
+	}
+
+	sb.selected = i
+	sb.PostEventWidgetContent(sb)
+	return true
+}
+
+func (sb *Sidebar) SelectNext() {
+	if sb.selected < len(sb.rows)-1 {
+		sb.selected++
+	}
+	sb.PostEventWidgetContent(sb)
+}
+
+func (sb *Sidebar) SelectPrev() {
+	if sb.selected > 0 {
+		sb.selected--
+	}
+	sb.PostEventWidgetContent(sb)
+}
+
+func (sb *Sidebar) SetFocused(focused bool) {
+	sb.focused = focused
+	sb.PostEventWidgetContent(sb)
+}
+
+// SetItems replaces the streams in the sidebar, keeping the selection on the
+// same key if it still exists.
+func (sb *Sidebar) SetItems(items []SidebarItem) {
+	key, _ := sb.Selected()
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Source < items[j].Source
+	})
+
+	var ns, pod string
+	sb.rows = sb.rows[:0]
+	for i := range items {
+		segs := strings.SplitN(items[i].Source, "/", 3)
+		if len(segs) != 3 {
+			continue
+		}
+
+		if segs[0] != ns {
+			ns, pod = segs[0], ""
+			sb.rows = append(sb.rows, sidebarRow{key: ns + "/", label: ns})
+		}
+		if segs[1] != pod {
+			pod = segs[1]
+			sb.rows = append(sb.rows, sidebarRow{key: ns + "/" + pod + "/", label: pod, depth: 1})
+		}
+		sb.rows = append(sb.rows, sidebarRow{key: items[i].Source, label: segs[2], depth: 2, item: &items[i]})
+	}
+
+	for i, row := range sb.rows {
+		if row.key == key {
+			sb.selected = i
+		}
+	}
+	sb.PostEventWidgetContent(sb)
+}
+
+// SetSourceState sets the function used to look up whether a key is muted
+// or soloed.
+func (sb *Sidebar) SetSourceState(fn func(key string) (bool, bool)) {
+	sb.state = fn
+	sb.PostEventWidgetContent(sb)
+}
+
+func (sb *Sidebar) SetView(v views.View) {
+	sb.v = v
+}
+
+func (sb *Sidebar) Size() (int, int) {
+	w := 0
+	for _, row := range sb.rows {
+		rw := 1 + 2*row.depth + runewidth.StringWidth(row.label)
+		if row.item != nil {
+			rw += runewidth.StringWidth(row.item.State) + 8
+		}
+		if rw > w {
+			w = rw
+		}
+	}
+
+	w++
+	if w > sidebarMaxWidth {
+		w = sidebarMaxWidth
+	}
+	return w, len(sb.rows)
+}
+
+// Within converts a screen position to a position in the sidebar, if it
+// falls within the sidebar.
+func (sb *Sidebar) Within(l *Locator, x, y int) (int, int, bool) {
+	return l.Within(sb.v, x, y)
+}
+
+// clamp keeps the selected row on screen.
+func (sb *Sidebar) clamp() {
+	_, h := sb.v.Size()
+	if sb.selected < sb.top {
+		sb.top = sb.selected
+	}
+	if sb.selected >= sb.top+h {
+		sb.top = sb.selected - h + 1
+	}
+	if sb.top < 0 {
+		sb.top = 0
+	}
+}
+
+func (sb *Sidebar) drawText(x, y, max int, s string, style tcell.Style) int {
+	for _, c := range s {
+		if x >= max {
+			break
+		}
+		sb.v.SetContent(x, y, c, nil, style)
+		x += runewidth.RuneWidth(c)
+	}
+	return x
+}
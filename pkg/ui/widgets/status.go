@@ -2,6 +2,9 @@ package widgets
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2/views"
 	"github.com/ripta/axe/pkg/ui/themes"
@@ -15,6 +18,9 @@ type Statusbar struct {
 
 	status  *views.Text
 	message *views.Text
+	filters *views.Text
+	column  *views.Text
+	clock   *views.Text
 	scroll  *views.Text
 }
 
@@ -25,6 +31,15 @@ func NewStatusbar(app *views.Application, style themes.Theme) *Statusbar {
 	message := views.NewText()
 	message.SetStyle(style.Statusbar.New)
 
+	filters := views.NewText()
+	filters.SetStyle(style.Statusbar.OK)
+
+	column := views.NewText()
+	column.SetStyle(style.Statusbar.Normal)
+
+	clock := views.NewText()
+	clock.SetStyle(style.Statusbar.Normal)
+
 	scroll := views.NewText()
 	scroll.SetStyle(style.Statusbar.New)
 
@@ -34,19 +49,64 @@ func NewStatusbar(app *views.Application, style themes.Theme) *Statusbar {
 
 		status:  status,
 		message: message,
+		filters: filters,
+		column:  column,
+		clock:   clock,
 		scroll:  scroll,
 	}
 
 	bar.AddWidget(status, 0)
 	bar.AddWidget(message, 1)
+	bar.AddWidget(filters, 0)
+	bar.AddWidget(column, 0)
+	bar.AddWidget(clock, 0)
 	bar.AddWidget(scroll, 0)
 	return bar
 }
 
+// SetClock displays the local time of the line at the top of the screen, or
+// nothing if there is none.
+func (bar *Statusbar) SetClock(t time.Time, ok bool) {
+	if !ok {
+		bar.clock.SetText("")
+		return
+	}
+	bar.clock.SetText(" " + t.Local().Format("15:04:05") + " ")
+}
+
+// SetColumn displays whether lines are wrapped, or else the first screen
+// column shown when scrolled horizontally. Nothing is shown at column zero.
+func (bar *Statusbar) SetColumn(offset int, wrap bool) {
+	switch {
+	case wrap:
+		bar.column.SetText(" WRAP ")
+	case offset > 0:
+		bar.column.SetText(fmt.Sprintf(" col %d ", offset+1))
+	default:
+		bar.column.SetText("")
+	}
+}
+
+// SetFilters displays the filter stack, or nothing if there are no filters.
+func (bar *Statusbar) SetFilters(fs []string) {
+	if len(fs) == 0 {
+		bar.filters.SetText("")
+		return
+	}
+	bar.filters.SetText(" " + strings.Join(fs, " ") + " ")
+}
+
 func (bar *Statusbar) SetMessage(s string) {
 	bar.message.SetText(" " + s + " ")
 }
 
+// SetPaused displays that the view is paused, and the number of new lines
+// held back since, in place of the status.
+func (bar *Statusbar) SetPaused(held int) {
+	bar.status.SetText(fmt.Sprintf(" PAUSED +%s new ", thousands(held)))
+	bar.status.SetStyle(bar.styles.Expired)
+}
+
 func (bar *Statusbar) SetScrollPercentage(pct int) {
 	bar.scroll.SetText(fmt.Sprintf(" %d%% ", pct))
 }
@@ -58,3 +118,15 @@ func (bar *Statusbar) SetStatus(s string, a themes.AltType) {
 	bar.status.SetText(fmt.Sprintf(" %-8s ", s))
 	bar.status.SetStyle(bar.styles.Select(a))
 }
+
+// thousands formats a number with commas between groups of three digits.
+func thousands(n int) string {
+	if n < 0 {
+		return "-" + thousands(-n)
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}